@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// LRU keeps at most size values for ttl each, evicting the least recently used one when full.
+type LRU[K comparable, V any] struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List
+	// generation changes with every removal, so a value loaded before a write finished is not cached after it.
+	generation uint64
+}
+
+func New[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value cached under key, if there is one that has not expired.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := element.Value.(*entry[K, V])
+	if !c.now().Before(e.expires) {
+		c.removeElement(element)
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+func (c *LRU[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(key, value)
+}
+
+// Load returns the value cached under key, or calls load and caches what it returns unless it fails.
+// It reports whether the value came from the cache. A value is not cached when entries were removed
+// while load ran, since it may predate the write they were removed for.
+func (c *LRU[K, V]) Load(key K, load func() (V, error)) (V, bool, error) {
+	if value, ok := c.Get(key); ok {
+		return value, true, nil
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return value, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.add(key, value)
+	}
+	return value, false, nil
+}
+
+func (c *LRU[K, V]) add(key K, value V) {
+	if c.size <= 0 {
+		return
+	}
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry[K, V])
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *LRU[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// RemoveFunc removes every entry for which remove returns true and returns how many it removed.
+func (c *LRU[K, V]) RemoveFunc(remove func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	removed := 0
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		e := element.Value.(*entry[K, V])
+		if remove(e.key, e.value) {
+			c.removeElement(element)
+			removed++
+		}
+		element = next
+	}
+	return removed
+}
+
+// Purge empties the cache.
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU[K, V]) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	_, ok := c.Get("a")
+	require.True(t, ok)
+	c.Add("c", 3)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok, "b was used least recently")
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](2, time.Minute)
+	c.now = func() time.Time { return now }
+	c.Add("a", 1)
+
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRULoad(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return 7, nil
+	}
+
+	value, hit, err := c.Load("a", load)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, 7, value)
+	value, hit, err = c.Load("a", load)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, 7, value)
+	assert.Equal(t, 1, loads)
+
+	_, _, err = c.Load("b", func() (int, error) { return 0, errors.New("no rows") })
+	assert.Error(t, err)
+	_, ok := c.Get("b")
+	assert.False(t, ok, "failed loads are not cached")
+}
+
+func TestLRULoadDropsValuesRacingARemoval(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+	_, _, err := c.Load("a", func() (int, error) {
+		c.Remove("a")
+		return 1, nil
+	})
+	require.NoError(t, err)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestLRURemoveFunc(t *testing.T) {
+	c := New[string, int](4, time.Minute)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+
+	assert.Equal(t, 2, c.RemoveFunc(func(key string, value int) bool { return key == "a" || value == 3 }))
+	assert.Equal(t, 1, c.Len())
+	_, ok := c.Get("b")
+	assert.True(t, ok)
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	handlers "github.com/mateuszkochelski/SwiftCodeDb/handlers"
+	jwt "github.com/mateuszkochelski/SwiftCodeDb/jwt"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const (
+	authModeAPIKey = "apikey"
+	authModeJWT    = "jwt"
+)
+
+var (
+	authMode     = flag.String("auth-mode", authModeAPIKey, "how bearer tokens are checked: apikey or jwt")
+	jwksSource   = flag.String("jwks", "", "file path or URL of the JWKS used to verify tokens in jwt mode")
+	jwtIssuer    = flag.String("jwt-issuer", "", "required iss claim in jwt mode")
+	jwtAudience  = flag.String("jwt-audience", "", "required aud claim in jwt mode")
+	jwtRoleClaim = flag.String("jwt-role-claim", "roles", "claim holding the caller's roles or groups in jwt mode")
+	jwtRoles     = flag.String("jwt-roles", "", "comma separated claim=role pairs, e.g. swift-admins=admin; by default claim values must be role names")
+)
+
+func tokenVerifier(conn *sql.DB) (handlers.TokenVerifier, error) {
+	switch *authMode {
+	case authModeAPIKey:
+		return handlers.NewAPIKeyVerifier(conn), nil
+	case authModeJWT:
+		if *jwksSource == "" || *jwtIssuer == "" || *jwtAudience == "" {
+			return nil, errors.New("jwt mode requires -jwks, -jwt-issuer and -jwt-audience")
+		}
+		roles, err := parseRoleMap(*jwtRoles)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := jwt.LoadKeySet(context.Background(), *jwksSource)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewJWTVerifier(jwt.NewValidator(keys, *jwtIssuer, *jwtAudience), *jwtRoleClaim, roles), nil
+	}
+	return nil, fmt.Errorf("unknown auth mode %q", *authMode)
+}
+
+func parseRoleMap(value string) (map[string]db.ApiKeyRole, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	roles := make(map[string]db.ApiKeyRole)
+	for _, pair := range strings.Split(value, ",") {
+		claim, role, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || claim == "" || !slices.Contains(store.APIKeyRoles, db.ApiKeyRole(role)) {
+			return nil, fmt.Errorf("invalid role mapping %q", pair)
+		}
+		roles[claim] = db.ApiKeyRole(role)
+	}
+	return roles, nil
+}
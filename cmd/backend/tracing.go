@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	traceExporterNone   = "none"
+	traceExporterStdout = "stdout"
+	traceExporterOTLP   = "otlp"
+
+	serviceName = "swift-codes-backend"
+)
+
+var (
+	traceExporter    = flag.String("trace-exporter", traceExporterNone, "where spans are sent: none, stdout or otlp")
+	otlpEndpoint     = flag.String("otlp-endpoint", "http://localhost:4318", "OTLP/HTTP collector spans are sent to with -trace-exporter=otlp")
+	traceSampleRatio = flag.Float64("trace-sample-ratio", 1, "fraction of new traces recorded; incoming traceparent headers keep their own decision")
+)
+
+// newTracerProvider returns the tracer provider configured by the flags, or nil when tracing is off.
+func newTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch *traceExporter {
+	case traceExporterNone:
+		return nil, nil
+	case traceExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case traceExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(*otlpEndpoint))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", *traceExporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*traceSampleRatio))),
+	), nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const apiKeyUsage = `usage: swiftdb apikey <create|revoke|list> [flags]
+
+  create -name NAME [-role reader|editor|admin]   issue a new key and print it once
+  revoke -name NAME                               revoke the active key with this name
+  list                                            show all keys, their roles and whether they are revoked
+`
+
+// runAPIKey manages the keys accepted by the backend in its Authorization header.
+func runAPIKey(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, apiKeyUsage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("apikey "+args[0], flag.ExitOnError)
+	source := flags.String("db", dbSource, "database connection string")
+	name := flags.String("name", "", "name identifying the key holder")
+	role := flags.String("role", string(db.ApiKeyRoleReader), "role granted to a created key: reader, editor or admin")
+	flags.Parse(args[1:])
+
+	if args[0] != "list" && *name == "" {
+		fmt.Fprintln(os.Stderr, "-name is required")
+		return 2
+	}
+	if !slices.Contains(store.APIKeyRoles, db.ApiKeyRole(*role)) {
+		fmt.Fprintf(os.Stderr, "unknown role %q\n", *role)
+		return 2
+	}
+
+	conn := openDB(*source)
+	defer conn.Close()
+	queries := db.New(conn)
+
+	switch args[0] {
+	case "create":
+		key, _, err := store.CreateAPIKey(context.Background(), queries, *name, db.ApiKeyRole(*role))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "creating key failed:", err)
+			return 2
+		}
+		fmt.Println(key)
+		return 0
+	case "revoke":
+		revoked, err := queries.RevokeApiKey(context.Background(), *name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "revoking key failed:", err)
+			return 2
+		}
+		if revoked == 0 {
+			fmt.Fprintf(os.Stderr, "no active key named %q\n", *name)
+			return 1
+		}
+		fmt.Printf("revoked key %q\n", *name)
+		return 0
+	case "list":
+		keys, err := queries.ListApiKeys(context.Background())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "listing keys failed:", err)
+			return 2
+		}
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tROLE\tCREATED\tREVOKED")
+		for _, key := range keys {
+			revokedAt := "-"
+			if key.RevokedAt.Valid {
+				revokedAt = key.RevokedAt.Time.Format(time.RFC3339)
+			}
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Role, key.CreatedAt.Format(time.RFC3339), revokedAt)
+		}
+		table.Flush()
+		return 0
+	default:
+		fmt.Fprint(os.Stderr, apiKeyUsage)
+		return 2
+	}
+}
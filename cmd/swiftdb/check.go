@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	consistency "github.com/mateuszkochelski/SwiftCodeDb/consistency"
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+)
+
+// runCheck exits with 1 when violations are found and 2 when the check itself fails.
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	source := flags.String("db", dbSource, "database connection string")
+	ignore := flags.String("ignore", "", "comma separated checks to skip: "+strings.Join(consistency.Checks, ", "))
+	flags.Parse(args)
+
+	conn := openDB(*source)
+	defer conn.Close()
+
+	report, err := consistency.Run(context.Background(), db.New(conn), splitList(*ignore)...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if report.Violations > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const purgeActor = "swiftdb purge"
+
+// runPurge permanently removes banks soft deleted longer ago than the retention period,
+// along with idempotency keys whose replay window has passed.
+func runPurge(args []string) int {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	source := flags.String("db", dbSource, "database connection string")
+	olderThan := flags.Duration("older-than", 30*24*time.Hour, "purge banks deleted longer ago than this")
+	flags.Parse(args)
+
+	if *olderThan < 0 {
+		fmt.Fprintln(os.Stderr, "older-than cannot be negative")
+		return 2
+	}
+
+	conn := openDB(*source)
+	defer conn.Close()
+
+	cutoff := sql.NullTime{Time: time.Now().Add(-*olderThan), Valid: true}
+	var purged []db.Bank
+	err := store.ExecTx(context.Background(), conn, func(queries *db.Queries) error {
+		var err error
+		purged, err = queries.PurgeDeletedBanks(context.Background(), cutoff)
+		if err != nil {
+			return err
+		}
+		for _, row := range purged {
+			before := models.ConvertToBank(row)
+			err = store.RecordAudit(context.Background(), queries, store.AuditMetadata{Actor: purgeActor}, db.AuditOperationPurge, row.SwiftCode, &before, nil)
+			if err != nil {
+				return err
+			}
+			err = store.NotifyChanges(context.Background(), queries, store.Change{SwiftCode: row.SwiftCode, CountryCode: row.CountryCode})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "purge failed:", err)
+		return 2
+	}
+
+	expiredKeys, err := db.New(conn).DeleteExpiredIdempotencyKeys(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "purging idempotency keys failed:", err)
+		return 2
+	}
+
+	fmt.Printf("purged %d banks deleted before %s\n", len(purged), cutoff.Time.Format(time.RFC3339))
+	fmt.Printf("purged %d expired idempotency keys\n", expiredKeys)
+	return 0
+}
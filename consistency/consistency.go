@@ -0,0 +1,118 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+)
+
+const (
+	CheckDuplicateSwiftCodes        = "duplicateSwiftCodes"
+	CheckBranchesWithoutHeadquarter = "branchesWithoutHeadquarter"
+	CheckCountryCodeMismatches      = "countryCodeMismatches"
+	CheckCountriesWithoutBanks      = "countriesWithoutBanks"
+	CheckEmptyAddresses             = "emptyAddresses"
+)
+
+var ErrUnknownCheck = errors.New("unknown check")
+
+var Checks = []string{
+	CheckDuplicateSwiftCodes,
+	CheckBranchesWithoutHeadquarter,
+	CheckCountryCodeMismatches,
+	CheckCountriesWithoutBanks,
+	CheckEmptyAddresses,
+}
+
+type DuplicateSwiftCode struct {
+	SwiftCode   string `json:"swiftCode"`
+	Occurrences int64  `json:"occurrences"`
+}
+
+type CountryCodeMismatch struct {
+	SwiftCode   string `json:"swiftCode"`
+	CountryISO2 string `json:"countryISO2"`
+}
+
+type Report struct {
+	DuplicateSwiftCodes        []DuplicateSwiftCode  `json:"duplicateSwiftCodes"`
+	BranchesWithoutHeadquarter []string              `json:"branchesWithoutHeadquarter"`
+	CountryCodeMismatches      []CountryCodeMismatch `json:"countryCodeMismatches"`
+	CountriesWithoutBanks      []string              `json:"countriesWithoutBanks"`
+	EmptyAddresses             []string              `json:"emptyAddresses"`
+	Violations                 int                   `json:"violations"`
+}
+
+// Run executes every check except the ignored ones. Ignored checks are reported as empty.
+func Run(ctx context.Context, queries *db.Queries, ignore ...string) (Report, error) {
+	skipped := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		if !slices.Contains(Checks, name) {
+			return Report{}, fmt.Errorf("%w %q", ErrUnknownCheck, name)
+		}
+		skipped[name] = true
+	}
+
+	report := Report{
+		DuplicateSwiftCodes:        []DuplicateSwiftCode{},
+		BranchesWithoutHeadquarter: []string{},
+		CountryCodeMismatches:      []CountryCodeMismatch{},
+		CountriesWithoutBanks:      []string{},
+		EmptyAddresses:             []string{},
+	}
+
+	if !skipped[CheckDuplicateSwiftCodes] {
+		rows, err := queries.FindDuplicateSwiftCodes(ctx)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s check failed %s", CheckDuplicateSwiftCodes, err.Error())
+		}
+		for _, row := range rows {
+			report.DuplicateSwiftCodes = append(report.DuplicateSwiftCodes, DuplicateSwiftCode{SwiftCode: row.SwiftCode, Occurrences: row.Occurrences})
+		}
+	}
+
+	if !skipped[CheckBranchesWithoutHeadquarter] {
+		codes, err := queries.FindBranchesWithoutHeadquarter(ctx)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s check failed %s", CheckBranchesWithoutHeadquarter, err.Error())
+		}
+		report.BranchesWithoutHeadquarter = append(report.BranchesWithoutHeadquarter, codes...)
+	}
+
+	if !skipped[CheckCountryCodeMismatches] {
+		rows, err := queries.FindCountryCodeMismatches(ctx)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s check failed %s", CheckCountryCodeMismatches, err.Error())
+		}
+		for _, row := range rows {
+			report.CountryCodeMismatches = append(report.CountryCodeMismatches, CountryCodeMismatch{SwiftCode: row.SwiftCode, CountryISO2: row.CountryCode})
+		}
+	}
+
+	if !skipped[CheckCountriesWithoutBanks] {
+		codes, err := queries.FindCountriesWithoutBanks(ctx)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s check failed %s", CheckCountriesWithoutBanks, err.Error())
+		}
+		report.CountriesWithoutBanks = append(report.CountriesWithoutBanks, codes...)
+	}
+
+	if !skipped[CheckEmptyAddresses] {
+		codes, err := queries.FindBanksWithEmptyAddress(ctx)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s check failed %s", CheckEmptyAddresses, err.Error())
+		}
+		report.EmptyAddresses = append(report.EmptyAddresses, codes...)
+	}
+
+	report.Violations = len(report.DuplicateSwiftCodes) +
+		len(report.BranchesWithoutHeadquarter) +
+		len(report.CountryCodeMismatches) +
+		len(report.CountriesWithoutBanks) +
+		len(report.EmptyAddresses)
+
+	return report, nil
+}
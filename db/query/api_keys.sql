@@ -0,0 +1,20 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+    name,
+    key_hash,
+    role
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetActiveApiKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL;
+
+-- name: ListApiKeys :many
+SELECT * FROM api_keys
+ORDER BY id;
+
+-- name: RevokeApiKey :execrows
+UPDATE api_keys SET revoked_at = now()
+WHERE name = $1 AND revoked_at IS NULL;
@@ -0,0 +1,16 @@
+-- name: CreateBankAudit :one
+INSERT INTO bank_audit (
+    operation,
+    swift_code,
+    before_data,
+    after_data,
+    actor,
+    request_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListBankAuditBySwiftCode :many
+SELECT * FROM bank_audit
+WHERE swift_code = $1
+ORDER BY id;
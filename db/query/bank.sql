@@ -4,28 +4,103 @@ INSERT INTO banks (
     bank_name,
     bank_address,
     country_code,
-    bank_type
+    bank_type,
+    institution_id,
+    valid_from,
+    valid_to
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetBankBySwiftCodeWithCountry :one
-SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type FROM banks as b 
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type, b.deleted_at, b.valid_from, b.valid_to, b.id, b.version FROM banks as b
 INNER JOIN countries as c ON b.country_code = c.country_code
-WHERE swift_code = $1 LIMIT 1;
+WHERE swift_code = sqlc.arg(swift_code) AND (b.deleted_at IS NULL OR sqlc.arg(include_deleted)::boolean)
+AND (b.valid_from IS NULL OR b.valid_from <= sqlc.arg(as_of)::date)
+AND (b.valid_to IS NULL OR b.valid_to > sqlc.arg(as_of)::date)
+ORDER BY b.deleted_at DESC NULLS FIRST
+LIMIT 1;
 
 -- name: GetBanksBranchesBySwiftCodePrefix :many
-SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type FROM banks as b 
-WHERE swift_code like $1 AND swift_code != $2;
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type, b.deleted_at, b.valid_from, b.valid_to FROM banks as b
+INNER JOIN institutions as i ON b.institution_id = i.id
+WHERE i.bic_prefix = sqlc.arg(bic_prefix) AND b.swift_code != sqlc.arg(swift_code)
+AND (b.deleted_at IS NULL OR sqlc.arg(include_deleted)::boolean)
+AND (b.valid_from IS NULL OR b.valid_from <= sqlc.arg(as_of)::date)
+AND (b.valid_to IS NULL OR b.valid_to > sqlc.arg(as_of)::date);
+
+-- name: GetBanksBySwiftCodes :many
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type, b.valid_from, b.valid_to FROM banks as b
+INNER JOIN countries as c ON b.country_code = c.country_code
+WHERE b.swift_code = ANY(sqlc.arg(swift_codes)::varchar[]) AND b.deleted_at IS NULL
+AND (b.valid_from IS NULL OR b.valid_from <= sqlc.arg(as_of)::date)
+AND (b.valid_to IS NULL OR b.valid_to > sqlc.arg(as_of)::date);
 
 -- name: GetBanksByCountryCode :many
-SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type FROM banks as b
-WHERE b.country_code = $1;
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type, b.deleted_at, b.valid_from, b.valid_to FROM banks as b
+WHERE b.country_code = sqlc.arg(country_code) AND (b.deleted_at IS NULL OR sqlc.arg(include_deleted)::boolean)
+AND (b.valid_from IS NULL OR b.valid_from <= sqlc.arg(as_of)::date)
+AND (b.valid_to IS NULL OR b.valid_to > sqlc.arg(as_of)::date);
 
--- name: DeleteBankBySwiftCode :one
-DELETE FROM banks
-WHERE $1 = swift_code RETURNING *;
+-- name: DeleteBank :one
+UPDATE banks SET deleted_at = now(), version = version + 1
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(version) AND deleted_at IS NULL RETURNING *;
+
+-- name: DeleteBankBySwiftCode :many
+UPDATE banks SET deleted_at = now(), version = version + 1
+WHERE $1 = swift_code AND deleted_at IS NULL RETURNING *;
+
+-- name: CountBanksBranchesBySwiftCodePrefix :one
+SELECT count(DISTINCT b.swift_code) FROM banks as b
+INNER JOIN institutions as i ON b.institution_id = i.id
+WHERE i.bic_prefix = $1 AND b.swift_code != $2 AND b.deleted_at IS NULL;
+
+-- name: DeleteBanksBranchesBySwiftCodePrefix :many
+UPDATE banks as b SET deleted_at = now(), version = b.version + 1
+FROM institutions as i
+WHERE b.institution_id = i.id AND i.bic_prefix = $1 AND b.swift_code != $2
+AND b.deleted_at IS NULL RETURNING b.*;
 
+-- name: CloseBankValidity :many
+UPDATE banks SET valid_to = sqlc.arg(valid_to), version = version + 1
+WHERE swift_code = sqlc.arg(swift_code) AND deleted_at IS NULL AND valid_to IS NULL
+AND (valid_from IS NULL OR valid_from < sqlc.arg(valid_to)) RETURNING *;
 
+-- name: CountOverlappingBanks :one
+SELECT count(*) FROM banks
+WHERE swift_code = sqlc.arg(swift_code) AND id <> sqlc.arg(exclude_id) AND deleted_at IS NULL
+AND (valid_to IS NULL OR valid_to > sqlc.arg(valid_from)::date)
+AND (valid_from IS NULL OR sqlc.narg(valid_to)::date IS NULL OR valid_from < sqlc.narg(valid_to)::date)
+AND NOT (sqlc.arg(supersedes)::boolean AND valid_to IS NULL AND (valid_from IS NULL OR valid_from < sqlc.arg(valid_from)::date));
 
+-- name: UpdateBank :one
+UPDATE banks SET
+    bank_name = sqlc.arg(bank_name),
+    bank_address = sqlc.arg(bank_address),
+    country_code = sqlc.arg(country_code),
+    valid_from = sqlc.arg(valid_from),
+    valid_to = sqlc.arg(valid_to),
+    version = version + 1
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(version) AND deleted_at IS NULL
+RETURNING *;
 
+-- name: RestoreBankBySwiftCode :many
+UPDATE banks SET deleted_at = NULL, version = version + 1
+WHERE swift_code = $1 AND deleted_at = (
+    SELECT max(d.deleted_at) FROM banks as d
+    WHERE d.swift_code = $1
+) RETURNING *;
+
+-- name: PurgeDeletedBanks :many
+DELETE FROM banks
+WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING *;
+
+-- name: ListBanksPage :many
+SELECT b.id, b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type, b.valid_from, b.valid_to FROM banks as b
+INNER JOIN countries as c ON b.country_code = c.country_code
+WHERE b.id > sqlc.arg(after_id) AND b.deleted_at IS NULL
+AND (sqlc.narg(country_code)::varchar IS NULL OR b.country_code = sqlc.narg(country_code))
+AND (b.valid_from IS NULL OR b.valid_from <= sqlc.arg(as_of)::date)
+AND (b.valid_to IS NULL OR b.valid_to > sqlc.arg(as_of)::date)
+ORDER BY b.id
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,34 @@
+-- name: FindDuplicateSwiftCodes :many
+SELECT swift_code, count(*) AS occurrences FROM banks
+WHERE deleted_at IS NULL
+AND (valid_from IS NULL OR valid_from <= CURRENT_DATE) AND (valid_to IS NULL OR valid_to > CURRENT_DATE)
+GROUP BY swift_code
+HAVING count(*) > 1
+ORDER BY swift_code;
+
+-- name: FindBranchesWithoutHeadquarter :many
+SELECT b.swift_code FROM banks as b
+WHERE b.bank_type = 'branch' AND b.deleted_at IS NULL
+AND (b.valid_from IS NULL OR b.valid_from <= CURRENT_DATE) AND (b.valid_to IS NULL OR b.valid_to > CURRENT_DATE) AND NOT EXISTS (
+    SELECT 1 FROM banks as h
+    WHERE h.institution_id = b.institution_id AND h.bank_type = 'headquarter' AND h.deleted_at IS NULL
+    AND (h.valid_from IS NULL OR h.valid_from <= CURRENT_DATE) AND (h.valid_to IS NULL OR h.valid_to > CURRENT_DATE)
+)
+ORDER BY b.swift_code;
+
+-- name: FindCountryCodeMismatches :many
+SELECT swift_code, country_code FROM banks
+WHERE SUBSTRING(swift_code FROM 5 FOR 2) != country_code AND deleted_at IS NULL
+ORDER BY swift_code;
+
+-- name: FindCountriesWithoutBanks :many
+SELECT c.country_code FROM countries as c
+WHERE NOT EXISTS (
+    SELECT 1 FROM banks as b WHERE b.country_code = c.country_code AND b.deleted_at IS NULL
+)
+ORDER BY c.country_code;
+
+-- name: FindBanksWithEmptyAddress :many
+SELECT swift_code FROM banks
+WHERE (bank_address IS NULL OR TRIM(bank_address) = '') AND deleted_at IS NULL
+ORDER BY swift_code;
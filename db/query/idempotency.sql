@@ -0,0 +1,32 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    key,
+    request_hash,
+    expires_at
+) VALUES (
+    sqlc.arg(key), sqlc.arg(request_hash), sqlc.arg(expires_at)
+) ON CONFLICT (key) DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+    status_code = 0,
+    response_body = NULL,
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at < now()
+OR (idempotency_keys.status_code = 0 AND idempotency_keys.created_at < sqlc.arg(stale_before))
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE key = $1;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys SET status_code = $2, response_body = $3
+WHERE key = $1;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE key = $1 AND status_code = 0;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < now();
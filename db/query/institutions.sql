@@ -0,0 +1,11 @@
+-- name: UpsertInstitution :one
+INSERT INTO institutions (
+    bic_prefix
+) VALUES (
+    $1
+) ON CONFLICT (bic_prefix) DO UPDATE SET bic_prefix = EXCLUDED.bic_prefix
+RETURNING *;
+
+-- name: GetInstitutionByPrefix :one
+SELECT * FROM institutions
+WHERE bic_prefix = $1;
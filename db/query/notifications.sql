@@ -0,0 +1,2 @@
+-- name: NotifySwiftChange :exec
+SELECT pg_notify('swift_changes', sqlc.arg(payload)::text);
@@ -0,0 +1,20 @@
+-- name: CreateBicRedirect :one
+INSERT INTO bic_redirects (
+    old_swift_code,
+    new_swift_code
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetBicRedirect :one
+SELECT * FROM bic_redirects
+WHERE old_swift_code = $1;
+
+-- name: ListBicRedirects :many
+SELECT * FROM bic_redirects
+ORDER BY old_swift_code;
+
+-- name: DeleteBicRedirect :one
+DELETE FROM bic_redirects
+WHERE old_swift_code = $1
+RETURNING *;
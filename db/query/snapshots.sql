@@ -0,0 +1,52 @@
+-- name: CreateSnapshot :one
+INSERT INTO snapshots (
+    name
+) VALUES (
+    $1
+) RETURNING *;
+
+-- name: CopyBanksToSnapshot :execrows
+INSERT INTO snapshot_banks (
+    snapshot_id,
+    swift_code,
+    bank_name,
+    bank_address,
+    country_code,
+    bank_type,
+    valid_from,
+    valid_to
+)
+SELECT DISTINCT ON (b.swift_code) sqlc.arg(snapshot_id)::bigint, b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type, b.valid_from, b.valid_to
+FROM banks as b
+WHERE b.deleted_at IS NULL
+AND (b.valid_from IS NULL OR b.valid_from <= CURRENT_DATE)
+AND (b.valid_to IS NULL OR b.valid_to > CURRENT_DATE)
+ORDER BY b.swift_code, b.id DESC;
+
+-- name: GetSnapshotByName :one
+SELECT * FROM snapshots
+WHERE name = $1;
+
+-- name: ListSnapshots :many
+SELECT s.id, s.name, s.created_at, count(sb.swift_code) AS bank_count FROM snapshots as s
+LEFT JOIN snapshot_banks as sb ON sb.snapshot_id = s.id
+GROUP BY s.id
+ORDER BY s.created_at, s.id;
+
+-- name: DiffSnapshots :many
+WITH a AS (
+    SELECT * FROM snapshot_banks WHERE snapshot_id = sqlc.arg(from_snapshot_id)
+), b AS (
+    SELECT * FROM snapshot_banks WHERE snapshot_id = sqlc.arg(to_snapshot_id)
+)
+SELECT
+    a.swift_code AS old_swift_code, a.bank_name AS old_bank_name, a.bank_address AS old_bank_address,
+    a.country_code AS old_country_code, a.bank_type AS old_bank_type, a.valid_from AS old_valid_from, a.valid_to AS old_valid_to,
+    b.swift_code AS new_swift_code, b.bank_name AS new_bank_name, b.bank_address AS new_bank_address,
+    b.country_code AS new_country_code, b.bank_type AS new_bank_type, b.valid_from AS new_valid_from, b.valid_to AS new_valid_to
+FROM a
+FULL OUTER JOIN b ON a.swift_code = b.swift_code
+WHERE a.swift_code IS NULL OR b.swift_code IS NULL
+OR (a.bank_name, a.bank_address, a.country_code, a.bank_type, a.valid_from, a.valid_to)
+    IS DISTINCT FROM (b.bank_name, b.bank_address, b.country_code, b.bank_type, b.valid_from, b.valid_to)
+ORDER BY COALESCE(a.swift_code, b.swift_code);
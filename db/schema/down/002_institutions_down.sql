@@ -0,0 +1,3 @@
+ALTER TABLE banks DROP COLUMN institution_id;
+
+drop TABLE institutions;
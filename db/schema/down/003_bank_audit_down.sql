@@ -0,0 +1,5 @@
+drop TABLE bank_audit;
+
+drop FUNCTION bank_audit_append_only;
+
+drop type audit_operation;
@@ -0,0 +1,3 @@
+DELETE FROM banks WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE banks DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE banks DROP COLUMN valid_to;
+
+ALTER TABLE banks DROP COLUMN valid_from;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS snapshot_banks;
+
+DROP TABLE IF EXISTS snapshots;
@@ -0,0 +1 @@
+ALTER TABLE banks DROP COLUMN version;
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys DROP COLUMN IF EXISTS "role";
+
+DROP TYPE IF EXISTS api_key_role;
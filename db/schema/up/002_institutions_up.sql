@@ -0,0 +1,22 @@
+CREATE TABLE "institutions" (
+  "id" bigserial PRIMARY KEY NOT NULL,
+  "bic_prefix" varchar(8) NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE institutions ADD CONSTRAINT bic_prefix_8_letters CHECK (char_length(bic_prefix) = 8);
+
+ALTER TABLE banks ADD COLUMN "institution_id" bigint;
+
+INSERT INTO institutions (bic_prefix)
+SELECT DISTINCT LEFT(swift_code, 8) FROM banks;
+
+UPDATE banks SET institution_id = i.id
+FROM institutions as i
+WHERE i.bic_prefix = LEFT(banks.swift_code, 8);
+
+ALTER TABLE banks ALTER COLUMN institution_id SET NOT NULL;
+
+ALTER TABLE banks ADD CONSTRAINT fk_banks_institution FOREIGN KEY (institution_id) REFERENCES institutions(id);
+
+CREATE INDEX ON "banks" ("institution_id");
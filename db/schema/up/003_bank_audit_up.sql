@@ -0,0 +1,27 @@
+CREATE TYPE "audit_operation" AS ENUM (
+  'create',
+  'delete'
+);
+
+CREATE TABLE "bank_audit" (
+  "id" bigserial PRIMARY KEY NOT NULL,
+  "operation" audit_operation NOT NULL,
+  "swift_code" varchar(11) NOT NULL,
+  "before_data" jsonb NOT NULL DEFAULT 'null',
+  "after_data" jsonb NOT NULL DEFAULT 'null',
+  "actor" TEXT NOT NULL,
+  "request_id" TEXT NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "bank_audit" ("swift_code");
+
+CREATE FUNCTION bank_audit_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'bank_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bank_audit_append_only
+BEFORE UPDATE OR DELETE ON bank_audit
+FOR EACH ROW EXECUTE FUNCTION bank_audit_append_only();
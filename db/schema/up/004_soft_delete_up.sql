@@ -0,0 +1,9 @@
+ALTER TABLE banks ADD COLUMN "deleted_at" timestamptz;
+
+CREATE INDEX ON "banks" ("swift_code") WHERE deleted_at IS NULL;
+
+CREATE INDEX ON "banks" ("deleted_at") WHERE deleted_at IS NOT NULL;
+
+ALTER TYPE audit_operation ADD VALUE 'restore';
+
+ALTER TYPE audit_operation ADD VALUE 'purge';
@@ -0,0 +1,7 @@
+ALTER TABLE banks ADD COLUMN "valid_from" date;
+
+ALTER TABLE banks ADD COLUMN "valid_to" date;
+
+ALTER TABLE banks ADD CONSTRAINT valid_to_after_valid_from CHECK (valid_to IS NULL OR valid_from IS NULL OR valid_to > valid_from);
+
+CREATE INDEX ON "banks" ("swift_code", "valid_from");
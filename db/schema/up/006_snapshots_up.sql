@@ -0,0 +1,21 @@
+CREATE TABLE "snapshots" (
+  "id" bigserial PRIMARY KEY NOT NULL,
+  "name" TEXT NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE snapshots ADD CONSTRAINT snapshot_name CHECK (LENGTH(name) > 0);
+
+CREATE TABLE "snapshot_banks" (
+  "snapshot_id" bigint NOT NULL,
+  "swift_code" varchar(11) NOT NULL,
+  "bank_name" TEXT NOT NULL,
+  "bank_address" TEXT,
+  "country_code" varchar(2) NOT NULL,
+  "bank_type" bank_type NOT NULL,
+  "valid_from" date,
+  "valid_to" date,
+  PRIMARY KEY ("snapshot_id", "swift_code")
+);
+
+ALTER TABLE snapshot_banks ADD CONSTRAINT fk_snapshot_banks_snapshot FOREIGN KEY (snapshot_id) REFERENCES snapshots(id) ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+CREATE TABLE "bic_redirects" (
+  "old_swift_code" varchar(11) PRIMARY KEY NOT NULL,
+  "new_swift_code" varchar(11) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE bic_redirects ADD CONSTRAINT redirect_to_other_code CHECK (old_swift_code != new_swift_code);
+
+CREATE INDEX ON "bic_redirects" ("new_swift_code");
@@ -0,0 +1,3 @@
+ALTER TABLE banks ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
+
+ALTER TYPE audit_operation ADD VALUE 'update';
@@ -0,0 +1,10 @@
+CREATE TABLE "idempotency_keys" (
+  "key" TEXT PRIMARY KEY NOT NULL,
+  "request_hash" TEXT NOT NULL,
+  "status_code" integer NOT NULL DEFAULT 0,
+  "response_body" bytea,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expires_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "idempotency_keys" ("expires_at");
@@ -0,0 +1,9 @@
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "key_hash" TEXT NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "revoked_at" timestamptz
+);
+
+CREATE UNIQUE INDEX ON "api_keys" ("name") WHERE "revoked_at" IS NULL;
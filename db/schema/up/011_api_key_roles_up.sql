@@ -0,0 +1,9 @@
+CREATE TYPE "api_key_role" AS ENUM (
+  'reader',
+  'editor',
+  'admin'
+);
+
+ALTER TABLE api_keys ADD COLUMN "role" api_key_role NOT NULL DEFAULT 'editor';
+
+ALTER TABLE api_keys ALTER COLUMN "role" DROP DEFAULT;
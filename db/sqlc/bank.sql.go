@@ -8,26 +8,128 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const closeBankValidity = `-- name: CloseBankValidity :many
+UPDATE banks SET valid_to = $1, version = version + 1
+WHERE swift_code = $2 AND deleted_at IS NULL AND valid_to IS NULL
+AND (valid_from IS NULL OR valid_from < $1) RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
+`
+
+type CloseBankValidityParams struct {
+	ValidTo   sql.NullTime `json:"valid_to"`
+	SwiftCode string       `json:"swift_code"`
+}
+
+func (q *Queries) CloseBankValidity(ctx context.Context, arg CloseBankValidityParams) ([]Bank, error) {
+	rows, err := q.db.QueryContext(ctx, closeBankValidity, arg.ValidTo, arg.SwiftCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bank
+	for rows.Next() {
+		var i Bank
+		if err := rows.Scan(
+			&i.ID,
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.BankType,
+			&i.InstitutionID,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countBanksBranchesBySwiftCodePrefix = `-- name: CountBanksBranchesBySwiftCodePrefix :one
+SELECT count(DISTINCT b.swift_code) FROM banks as b
+INNER JOIN institutions as i ON b.institution_id = i.id
+WHERE i.bic_prefix = $1 AND b.swift_code != $2 AND b.deleted_at IS NULL
+`
+
+type CountBanksBranchesBySwiftCodePrefixParams struct {
+	BicPrefix string `json:"bic_prefix"`
+	SwiftCode string `json:"swift_code"`
+}
+
+func (q *Queries) CountBanksBranchesBySwiftCodePrefix(ctx context.Context, arg CountBanksBranchesBySwiftCodePrefixParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBanksBranchesBySwiftCodePrefix, arg.BicPrefix, arg.SwiftCode)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOverlappingBanks = `-- name: CountOverlappingBanks :one
+SELECT count(*) FROM banks
+WHERE swift_code = $1 AND id <> $2 AND deleted_at IS NULL
+AND (valid_to IS NULL OR valid_to > $3::date)
+AND (valid_from IS NULL OR $4::date IS NULL OR valid_from < $4::date)
+AND NOT ($5::boolean AND valid_to IS NULL AND (valid_from IS NULL OR valid_from < $3::date))
+`
+
+type CountOverlappingBanksParams struct {
+	SwiftCode  string       `json:"swift_code"`
+	ExcludeID  int64        `json:"exclude_id"`
+	ValidFrom  time.Time    `json:"valid_from"`
+	ValidTo    sql.NullTime `json:"valid_to"`
+	Supersedes bool         `json:"supersedes"`
+}
+
+func (q *Queries) CountOverlappingBanks(ctx context.Context, arg CountOverlappingBanksParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOverlappingBanks,
+		arg.SwiftCode,
+		arg.ExcludeID,
+		arg.ValidFrom,
+		arg.ValidTo,
+		arg.Supersedes,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBank = `-- name: CreateBank :one
 INSERT INTO banks (
     swift_code,
     bank_name,
     bank_address,
     country_code,
-    bank_type
+    bank_type,
+    institution_id,
+    valid_from,
+    valid_to
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
 `
 
 type CreateBankParams struct {
-	SwiftCode   string         `json:"swift_code"`
-	BankName    string         `json:"bank_name"`
-	BankAddress sql.NullString `json:"bank_address"`
-	CountryCode string         `json:"country_code"`
-	BankType    BankType       `json:"bank_type"`
+	SwiftCode     string         `json:"swift_code"`
+	BankName      string         `json:"bank_name"`
+	BankAddress   sql.NullString `json:"bank_address"`
+	CountryCode   string         `json:"country_code"`
+	BankType      BankType       `json:"bank_type"`
+	InstitutionID int64          `json:"institution_id"`
+	ValidFrom     sql.NullTime   `json:"valid_from"`
+	ValidTo       sql.NullTime   `json:"valid_to"`
 }
 
 func (q *Queries) CreateBank(ctx context.Context, arg CreateBankParams) (Bank, error) {
@@ -37,6 +139,9 @@ func (q *Queries) CreateBank(ctx context.Context, arg CreateBankParams) (Bank, e
 		arg.BankAddress,
 		arg.CountryCode,
 		arg.BankType,
+		arg.InstitutionID,
+		arg.ValidFrom,
+		arg.ValidTo,
 	)
 	var i Bank
 	err := row.Scan(
@@ -46,17 +151,27 @@ func (q *Queries) CreateBank(ctx context.Context, arg CreateBankParams) (Bank, e
 		&i.BankAddress,
 		&i.CountryCode,
 		&i.BankType,
+		&i.InstitutionID,
+		&i.DeletedAt,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Version,
 	)
 	return i, err
 }
 
-const deleteBankBySwiftCode = `-- name: DeleteBankBySwiftCode :one
-DELETE FROM banks
-WHERE $1 = swift_code RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type
+const deleteBank = `-- name: DeleteBank :one
+UPDATE banks SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND version = $2 AND deleted_at IS NULL RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
 `
 
-func (q *Queries) DeleteBankBySwiftCode(ctx context.Context, swiftCode string) (Bank, error) {
-	row := q.db.QueryRowContext(ctx, deleteBankBySwiftCode, swiftCode)
+type DeleteBankParams struct {
+	ID      int64 `json:"id"`
+	Version int64 `json:"version"`
+}
+
+func (q *Queries) DeleteBank(ctx context.Context, arg DeleteBankParams) (Bank, error) {
+	row := q.db.QueryRowContext(ctx, deleteBank, arg.ID, arg.Version)
 	var i Bank
 	err := row.Scan(
 		&i.ID,
@@ -65,16 +180,118 @@ func (q *Queries) DeleteBankBySwiftCode(ctx context.Context, swiftCode string) (
 		&i.BankAddress,
 		&i.CountryCode,
 		&i.BankType,
+		&i.InstitutionID,
+		&i.DeletedAt,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Version,
 	)
 	return i, err
 }
 
+const deleteBankBySwiftCode = `-- name: DeleteBankBySwiftCode :many
+UPDATE banks SET deleted_at = now(), version = version + 1
+WHERE $1 = swift_code AND deleted_at IS NULL RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
+`
+
+func (q *Queries) DeleteBankBySwiftCode(ctx context.Context, swiftCode string) ([]Bank, error) {
+	rows, err := q.db.QueryContext(ctx, deleteBankBySwiftCode, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bank
+	for rows.Next() {
+		var i Bank
+		if err := rows.Scan(
+			&i.ID,
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.BankType,
+			&i.InstitutionID,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteBanksBranchesBySwiftCodePrefix = `-- name: DeleteBanksBranchesBySwiftCodePrefix :many
+UPDATE banks as b SET deleted_at = now(), version = b.version + 1
+FROM institutions as i
+WHERE b.institution_id = i.id AND i.bic_prefix = $1 AND b.swift_code != $2
+AND b.deleted_at IS NULL RETURNING b.id, b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type, b.institution_id, b.deleted_at, b.valid_from, b.valid_to, b.version
+`
+
+type DeleteBanksBranchesBySwiftCodePrefixParams struct {
+	BicPrefix string `json:"bic_prefix"`
+	SwiftCode string `json:"swift_code"`
+}
+
+func (q *Queries) DeleteBanksBranchesBySwiftCodePrefix(ctx context.Context, arg DeleteBanksBranchesBySwiftCodePrefixParams) ([]Bank, error) {
+	rows, err := q.db.QueryContext(ctx, deleteBanksBranchesBySwiftCodePrefix, arg.BicPrefix, arg.SwiftCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bank
+	for rows.Next() {
+		var i Bank
+		if err := rows.Scan(
+			&i.ID,
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.BankType,
+			&i.InstitutionID,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBankBySwiftCodeWithCountry = `-- name: GetBankBySwiftCodeWithCountry :one
-SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type FROM banks as b 
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type, b.deleted_at, b.valid_from, b.valid_to, b.id, b.version FROM banks as b
 INNER JOIN countries as c ON b.country_code = c.country_code
-WHERE swift_code = $1 LIMIT 1
+WHERE swift_code = $1 AND (b.deleted_at IS NULL OR $2::boolean)
+AND (b.valid_from IS NULL OR b.valid_from <= $3::date)
+AND (b.valid_to IS NULL OR b.valid_to > $3::date)
+ORDER BY b.deleted_at DESC NULLS FIRST
+LIMIT 1
 `
 
+type GetBankBySwiftCodeWithCountryParams struct {
+	SwiftCode      string    `json:"swift_code"`
+	IncludeDeleted bool      `json:"include_deleted"`
+	AsOf           time.Time `json:"as_of"`
+}
+
 type GetBankBySwiftCodeWithCountryRow struct {
 	SwiftCode   string         `json:"swift_code"`
 	BankName    string         `json:"bank_name"`
@@ -82,10 +299,15 @@ type GetBankBySwiftCodeWithCountryRow struct {
 	CountryCode string         `json:"country_code"`
 	CountryName string         `json:"country_name"`
 	BankType    BankType       `json:"bank_type"`
+	DeletedAt   sql.NullTime   `json:"deleted_at"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
+	ID          int64          `json:"id"`
+	Version     int64          `json:"version"`
 }
 
-func (q *Queries) GetBankBySwiftCodeWithCountry(ctx context.Context, swiftCode string) (GetBankBySwiftCodeWithCountryRow, error) {
-	row := q.db.QueryRowContext(ctx, getBankBySwiftCodeWithCountry, swiftCode)
+func (q *Queries) GetBankBySwiftCodeWithCountry(ctx context.Context, arg GetBankBySwiftCodeWithCountryParams) (GetBankBySwiftCodeWithCountryRow, error) {
+	row := q.db.QueryRowContext(ctx, getBankBySwiftCodeWithCountry, arg.SwiftCode, arg.IncludeDeleted, arg.AsOf)
 	var i GetBankBySwiftCodeWithCountryRow
 	err := row.Scan(
 		&i.SwiftCode,
@@ -94,18 +316,29 @@ func (q *Queries) GetBankBySwiftCodeWithCountry(ctx context.Context, swiftCode s
 		&i.CountryCode,
 		&i.CountryName,
 		&i.BankType,
+		&i.DeletedAt,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.ID,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getBanksBranchesBySwiftCodePrefix = `-- name: GetBanksBranchesBySwiftCodePrefix :many
-SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type FROM banks as b 
-WHERE swift_code like $1 AND swift_code != $2
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type, b.deleted_at, b.valid_from, b.valid_to FROM banks as b
+INNER JOIN institutions as i ON b.institution_id = i.id
+WHERE i.bic_prefix = $1 AND b.swift_code != $2
+AND (b.deleted_at IS NULL OR $3::boolean)
+AND (b.valid_from IS NULL OR b.valid_from <= $4::date)
+AND (b.valid_to IS NULL OR b.valid_to > $4::date)
 `
 
 type GetBanksBranchesBySwiftCodePrefixParams struct {
-	SwiftCode   string `json:"swift_code"`
-	SwiftCode_2 string `json:"swift_code_2"`
+	BicPrefix      string    `json:"bic_prefix"`
+	SwiftCode      string    `json:"swift_code"`
+	IncludeDeleted bool      `json:"include_deleted"`
+	AsOf           time.Time `json:"as_of"`
 }
 
 type GetBanksBranchesBySwiftCodePrefixRow struct {
@@ -114,10 +347,13 @@ type GetBanksBranchesBySwiftCodePrefixRow struct {
 	BankAddress sql.NullString `json:"bank_address"`
 	CountryCode string         `json:"country_code"`
 	BankType    BankType       `json:"bank_type"`
+	DeletedAt   sql.NullTime   `json:"deleted_at"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
 }
 
 func (q *Queries) GetBanksBranchesBySwiftCodePrefix(ctx context.Context, arg GetBanksBranchesBySwiftCodePrefixParams) ([]GetBanksBranchesBySwiftCodePrefixRow, error) {
-	rows, err := q.db.QueryContext(ctx, getBanksBranchesBySwiftCodePrefix, arg.SwiftCode, arg.SwiftCode_2)
+	rows, err := q.db.QueryContext(ctx, getBanksBranchesBySwiftCodePrefix, arg.BicPrefix, arg.SwiftCode, arg.IncludeDeleted, arg.AsOf)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +367,9 @@ func (q *Queries) GetBanksBranchesBySwiftCodePrefix(ctx context.Context, arg Get
 			&i.BankAddress,
 			&i.CountryCode,
 			&i.BankType,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
 		); err != nil {
 			return nil, err
 		}
@@ -146,20 +385,31 @@ func (q *Queries) GetBanksBranchesBySwiftCodePrefix(ctx context.Context, arg Get
 }
 
 const getBanksByCountryCode = `-- name: GetBanksByCountryCode :many
-SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type FROM banks as b
-WHERE b.country_code = $1
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, b.bank_type, b.deleted_at, b.valid_from, b.valid_to FROM banks as b
+WHERE b.country_code = $1 AND (b.deleted_at IS NULL OR $2::boolean)
+AND (b.valid_from IS NULL OR b.valid_from <= $3::date)
+AND (b.valid_to IS NULL OR b.valid_to > $3::date)
 `
 
+type GetBanksByCountryCodeParams struct {
+	CountryCode    string    `json:"country_code"`
+	IncludeDeleted bool      `json:"include_deleted"`
+	AsOf           time.Time `json:"as_of"`
+}
+
 type GetBanksByCountryCodeRow struct {
 	SwiftCode   string         `json:"swift_code"`
 	BankName    string         `json:"bank_name"`
 	BankAddress sql.NullString `json:"bank_address"`
 	CountryCode string         `json:"country_code"`
 	BankType    BankType       `json:"bank_type"`
+	DeletedAt   sql.NullTime   `json:"deleted_at"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
 }
 
-func (q *Queries) GetBanksByCountryCode(ctx context.Context, countryCode string) ([]GetBanksByCountryCodeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getBanksByCountryCode, countryCode)
+func (q *Queries) GetBanksByCountryCode(ctx context.Context, arg GetBanksByCountryCodeParams) ([]GetBanksByCountryCodeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBanksByCountryCode, arg.CountryCode, arg.IncludeDeleted, arg.AsOf)
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +423,9 @@ func (q *Queries) GetBanksByCountryCode(ctx context.Context, countryCode string)
 			&i.BankAddress,
 			&i.CountryCode,
 			&i.BankType,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
 		); err != nil {
 			return nil, err
 		}
@@ -186,3 +439,254 @@ func (q *Queries) GetBanksByCountryCode(ctx context.Context, countryCode string)
 	}
 	return items, nil
 }
+
+const getBanksBySwiftCodes = `-- name: GetBanksBySwiftCodes :many
+SELECT b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type, b.valid_from, b.valid_to FROM banks as b
+INNER JOIN countries as c ON b.country_code = c.country_code
+WHERE b.swift_code = ANY($1::varchar[]) AND b.deleted_at IS NULL
+AND (b.valid_from IS NULL OR b.valid_from <= $2::date)
+AND (b.valid_to IS NULL OR b.valid_to > $2::date)
+`
+
+type GetBanksBySwiftCodesParams struct {
+	SwiftCodes []string  `json:"swift_codes"`
+	AsOf       time.Time `json:"as_of"`
+}
+
+type GetBanksBySwiftCodesRow struct {
+	SwiftCode   string         `json:"swift_code"`
+	BankName    string         `json:"bank_name"`
+	BankAddress sql.NullString `json:"bank_address"`
+	CountryCode string         `json:"country_code"`
+	CountryName string         `json:"country_name"`
+	BankType    BankType       `json:"bank_type"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
+}
+
+func (q *Queries) GetBanksBySwiftCodes(ctx context.Context, arg GetBanksBySwiftCodesParams) ([]GetBanksBySwiftCodesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBanksBySwiftCodes, pq.Array(arg.SwiftCodes), arg.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBanksBySwiftCodesRow
+	for rows.Next() {
+		var i GetBanksBySwiftCodesRow
+		if err := rows.Scan(
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.CountryName,
+			&i.BankType,
+			&i.ValidFrom,
+			&i.ValidTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBanksPage = `-- name: ListBanksPage :many
+SELECT b.id, b.swift_code, b.bank_name, b.bank_address, b.country_code, c.country_name, b.bank_type, b.valid_from, b.valid_to FROM banks as b
+INNER JOIN countries as c ON b.country_code = c.country_code
+WHERE b.id > $1 AND b.deleted_at IS NULL
+AND ($2::varchar IS NULL OR b.country_code = $2)
+AND (b.valid_from IS NULL OR b.valid_from <= $3::date)
+AND (b.valid_to IS NULL OR b.valid_to > $3::date)
+ORDER BY b.id
+LIMIT $4
+`
+
+type ListBanksPageParams struct {
+	AfterID     int64          `json:"after_id"`
+	CountryCode sql.NullString `json:"country_code"`
+	AsOf        time.Time      `json:"as_of"`
+	PageSize    int32          `json:"page_size"`
+}
+
+type ListBanksPageRow struct {
+	ID          int64          `json:"id"`
+	SwiftCode   string         `json:"swift_code"`
+	BankName    string         `json:"bank_name"`
+	BankAddress sql.NullString `json:"bank_address"`
+	CountryCode string         `json:"country_code"`
+	CountryName string         `json:"country_name"`
+	BankType    BankType       `json:"bank_type"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
+}
+
+func (q *Queries) ListBanksPage(ctx context.Context, arg ListBanksPageParams) ([]ListBanksPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBanksPage, arg.AfterID, arg.CountryCode, arg.AsOf, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBanksPageRow
+	for rows.Next() {
+		var i ListBanksPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.CountryName,
+			&i.BankType,
+			&i.ValidFrom,
+			&i.ValidTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedBanks = `-- name: PurgeDeletedBanks :many
+DELETE FROM banks
+WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
+`
+
+func (q *Queries) PurgeDeletedBanks(ctx context.Context, deletedAt sql.NullTime) ([]Bank, error) {
+	rows, err := q.db.QueryContext(ctx, purgeDeletedBanks, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bank
+	for rows.Next() {
+		var i Bank
+		if err := rows.Scan(
+			&i.ID,
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.BankType,
+			&i.InstitutionID,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreBankBySwiftCode = `-- name: RestoreBankBySwiftCode :many
+UPDATE banks SET deleted_at = NULL, version = version + 1
+WHERE swift_code = $1 AND deleted_at = (
+    SELECT max(d.deleted_at) FROM banks as d
+    WHERE d.swift_code = $1
+) RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
+`
+
+func (q *Queries) RestoreBankBySwiftCode(ctx context.Context, swiftCode string) ([]Bank, error) {
+	rows, err := q.db.QueryContext(ctx, restoreBankBySwiftCode, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bank
+	for rows.Next() {
+		var i Bank
+		if err := rows.Scan(
+			&i.ID,
+			&i.SwiftCode,
+			&i.BankName,
+			&i.BankAddress,
+			&i.CountryCode,
+			&i.BankType,
+			&i.InstitutionID,
+			&i.DeletedAt,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateBank = `-- name: UpdateBank :one
+UPDATE banks SET
+    bank_name = $1,
+    bank_address = $2,
+    country_code = $3,
+    valid_from = $4,
+    valid_to = $5,
+    version = version + 1
+WHERE id = $6 AND version = $7 AND deleted_at IS NULL
+RETURNING id, swift_code, bank_name, bank_address, country_code, bank_type, institution_id, deleted_at, valid_from, valid_to, version
+`
+
+type UpdateBankParams struct {
+	BankName    string         `json:"bank_name"`
+	BankAddress sql.NullString `json:"bank_address"`
+	CountryCode string         `json:"country_code"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
+	ID          int64          `json:"id"`
+	Version     int64          `json:"version"`
+}
+
+func (q *Queries) UpdateBank(ctx context.Context, arg UpdateBankParams) (Bank, error) {
+	row := q.db.QueryRowContext(ctx, updateBank,
+		arg.BankName,
+		arg.BankAddress,
+		arg.CountryCode,
+		arg.ValidFrom,
+		arg.ValidTo,
+		arg.ID,
+		arg.Version,
+	)
+	var i Bank
+	err := row.Scan(
+		&i.ID,
+		&i.SwiftCode,
+		&i.BankName,
+		&i.BankAddress,
+		&i.CountryCode,
+		&i.BankType,
+		&i.InstitutionID,
+		&i.DeletedAt,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Version,
+	)
+	return i, err
+}
@@ -14,10 +14,11 @@ func Test_create_bank_succeded_when_swift_has_11_letters_country_uppercase_iso_c
 		CountryName: "POLAND",
 	}
 	bankParams := CreateBankParams{
-		SwiftCode:   "12345678XXX",
-		BankName:    "Pekao",
-		CountryCode: "PL",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678XXX",
+		BankName:      "Pekao",
+		CountryCode:   "PL",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 	country, err := testQueries.CreateCountry(context.Background(), countryParams)
 	require.NoError(t, err)
@@ -30,10 +31,11 @@ func Test_create_bank_succeded_when_swift_has_11_letters_country_uppercase_iso_c
 
 func Test_create_bank_error_when_swift_code_hasnt_11_letters(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678XXXX",
-		BankName:    "Pekao",
-		CountryCode: "PL",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678XXXX",
+		BankName:      "Pekao",
+		CountryCode:   "PL",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 
 	bank, err := testQueries.CreateBank(context.Background(), arg)
@@ -42,10 +44,11 @@ func Test_create_bank_error_when_swift_code_hasnt_11_letters(t *testing.T) {
 }
 func Test_create_bank_error_when_country_code_not_uppercase(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678XXXX",
-		BankName:    "Pekao",
-		CountryCode: "Pl",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678XXXX",
+		BankName:      "Pekao",
+		CountryCode:   "Pl",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 
 	bank, err := testQueries.CreateBank(context.Background(), arg)
@@ -55,10 +58,11 @@ func Test_create_bank_error_when_country_code_not_uppercase(t *testing.T) {
 
 func Test_create_bank_error_when_country_name_not_uppercase(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678XXXX",
-		BankName:    "Pekao",
-		CountryCode: "EN",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678XXXX",
+		BankName:      "Pekao",
+		CountryCode:   "EN",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 
 	bank, err := testQueries.CreateBank(context.Background(), arg)
@@ -68,10 +72,11 @@ func Test_create_bank_error_when_country_name_not_uppercase(t *testing.T) {
 
 func Test_create_bank_error_swift_code_not_ends_with_xxx_and_bank_type_headquarter(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678123",
-		BankName:    "Pekao",
-		CountryCode: "EN",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678123",
+		BankName:      "Pekao",
+		CountryCode:   "EN",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 
 	bank, err := testQueries.CreateBank(context.Background(), arg)
@@ -85,10 +90,11 @@ func Test_create_bank_succeed_swift_code_ends_with_xxx_and_bank_type_headquarter
 		CountryName: "ENGLAND",
 	}
 	bankArg := CreateBankParams{
-		SwiftCode:   "12345678XXX",
-		BankName:    "Pekao",
-		CountryCode: "EN",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678XXX",
+		BankName:      "Pekao",
+		CountryCode:   "EN",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 	country, err := testQueries.CreateCountry(context.Background(), countryArg)
 	require.NoError(t, err)
@@ -100,10 +106,11 @@ func Test_create_bank_succeed_swift_code_ends_with_xxx_and_bank_type_headquarter
 
 func Test_create_bank_succeed_swift_code_not_ends_with_xxx_and_bank_type_branch(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678ASD",
-		BankName:    "Pekao",
-		CountryCode: "EN",
-		BankType:    BankTypeBranch,
+		SwiftCode:     "12345678ASD",
+		BankName:      "Pekao",
+		CountryCode:   "EN",
+		BankType:      BankTypeBranch,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 
 	bank, err := testQueries.CreateBank(context.Background(), arg)
@@ -113,10 +120,11 @@ func Test_create_bank_succeed_swift_code_not_ends_with_xxx_and_bank_type_branch(
 
 func Test_create_bank_error_swift_code_ends_with_xxx_and_bank_type_branch(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678XXX",
-		BankName:    "Pekao",
-		CountryCode: "EN",
-		BankType:    BankTypeBranch,
+		SwiftCode:     "12345678XXX",
+		BankName:      "Pekao",
+		CountryCode:   "EN",
+		BankType:      BankTypeBranch,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 
 	bank, err := testQueries.CreateBank(context.Background(), arg)
@@ -126,9 +134,10 @@ func Test_create_bank_error_swift_code_ends_with_xxx_and_bank_type_branch(t *tes
 
 func Test_create_bank_error_empty_bank_name(t *testing.T) {
 	arg := CreateBankParams{
-		SwiftCode:   "12345678XXX",
-		CountryCode: "EN",
-		BankType:    BankTypeHeadquarter,
+		SwiftCode:     "12345678XXX",
+		CountryCode:   "EN",
+		BankType:      BankTypeHeadquarter,
+		InstitutionID: createTestInstitution(t, "12345678"),
 	}
 	bank, err := testQueries.CreateBank(context.Background(), arg)
 	require.Error(t, err)
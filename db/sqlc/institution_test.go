@@ -0,0 +1,26 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_upsert_institution_returns_same_row_for_same_prefix(t *testing.T) {
+	first, err := testQueries.UpsertInstitution(context.Background(), "INSTPLPW")
+	require.NoError(t, err)
+	second, err := testQueries.UpsertInstitution(context.Background(), "INSTPLPW")
+	require.NoError(t, err)
+	require.Equal(t, first.ID, second.ID)
+
+	found, err := testQueries.GetInstitutionByPrefix(context.Background(), "INSTPLPW")
+	require.NoError(t, err)
+	require.Equal(t, first.ID, found.ID)
+}
+
+func Test_upsert_institution_error_when_prefix_hasnt_8_letters(t *testing.T) {
+	institution, err := testQueries.UpsertInstitution(context.Background(), "INSTPL")
+	require.Error(t, err)
+	require.Empty(t, institution)
+}
@@ -1,12 +1,14 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
 	"testing"
 
 	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -29,3 +31,9 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func createTestInstitution(t *testing.T, bicPrefix string) int64 {
+	institution, err := testQueries.UpsertInstitution(context.Background(), bicPrefix)
+	require.NoError(t, err)
+	return institution.ID
+}
@@ -7,9 +7,99 @@ package db
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
+type ApiKeyRole string
+
+const (
+	ApiKeyRoleReader ApiKeyRole = "reader"
+	ApiKeyRoleEditor ApiKeyRole = "editor"
+	ApiKeyRoleAdmin  ApiKeyRole = "admin"
+)
+
+func (e *ApiKeyRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ApiKeyRole(s)
+	case string:
+		*e = ApiKeyRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ApiKeyRole: %T", src)
+	}
+	return nil
+}
+
+type NullApiKeyRole struct {
+	ApiKeyRole ApiKeyRole `json:"api_key_role"`
+	Valid      bool       `json:"valid"` // Valid is true if ApiKeyRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullApiKeyRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.ApiKeyRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ApiKeyRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullApiKeyRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ApiKeyRole), nil
+}
+
+type AuditOperation string
+
+const (
+	AuditOperationCreate  AuditOperation = "create"
+	AuditOperationDelete  AuditOperation = "delete"
+	AuditOperationRestore AuditOperation = "restore"
+	AuditOperationPurge   AuditOperation = "purge"
+	AuditOperationUpdate  AuditOperation = "update"
+)
+
+func (e *AuditOperation) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AuditOperation(s)
+	case string:
+		*e = AuditOperation(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AuditOperation: %T", src)
+	}
+	return nil
+}
+
+type NullAuditOperation struct {
+	AuditOperation AuditOperation `json:"audit_operation"`
+	Valid          bool           `json:"valid"` // Valid is true if AuditOperation is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAuditOperation) Scan(value interface{}) error {
+	if value == nil {
+		ns.AuditOperation, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AuditOperation.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAuditOperation) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AuditOperation), nil
+}
+
 type BankType string
 
 const (
@@ -52,16 +142,79 @@ func (ns NullBankType) Value() (driver.Value, error) {
 	return string(ns.BankType), nil
 }
 
+type ApiKey struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	KeyHash   string       `json:"key_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+	Role      ApiKeyRole   `json:"role"`
+}
+
 type Bank struct {
-	ID          int64          `json:"id"`
-	SwiftCode   string         `json:"swift_code"`
-	BankName    string         `json:"bank_name"`
-	BankAddress sql.NullString `json:"bank_address"`
-	CountryCode string         `json:"country_code"`
-	BankType    BankType       `json:"bank_type"`
+	ID            int64          `json:"id"`
+	SwiftCode     string         `json:"swift_code"`
+	BankName      string         `json:"bank_name"`
+	BankAddress   sql.NullString `json:"bank_address"`
+	CountryCode   string         `json:"country_code"`
+	BankType      BankType       `json:"bank_type"`
+	InstitutionID int64          `json:"institution_id"`
+	DeletedAt     sql.NullTime   `json:"deleted_at"`
+	ValidFrom     sql.NullTime   `json:"valid_from"`
+	ValidTo       sql.NullTime   `json:"valid_to"`
+	Version       int64          `json:"version"`
+}
+
+type BankAudit struct {
+	ID         int64           `json:"id"`
+	Operation  AuditOperation  `json:"operation"`
+	SwiftCode  string          `json:"swift_code"`
+	BeforeData json.RawMessage `json:"before_data"`
+	AfterData  json.RawMessage `json:"after_data"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type BicRedirect struct {
+	OldSwiftCode string    `json:"old_swift_code"`
+	NewSwiftCode string    `json:"new_swift_code"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Country struct {
 	CountryCode string `json:"country_code"`
 	CountryName string `json:"country_name"`
 }
+
+type IdempotencyKey struct {
+	Key          string    `json:"key"`
+	RequestHash  string    `json:"request_hash"`
+	StatusCode   int32     `json:"status_code"`
+	ResponseBody []byte    `json:"response_body"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type Institution struct {
+	ID        int64     `json:"id"`
+	BicPrefix string    `json:"bic_prefix"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Snapshot struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SnapshotBank struct {
+	SnapshotID  int64          `json:"snapshot_id"`
+	SwiftCode   string         `json:"swift_code"`
+	BankName    string         `json:"bank_name"`
+	BankAddress sql.NullString `json:"bank_address"`
+	CountryCode string         `json:"country_code"`
+	BankType    BankType       `json:"bank_type"`
+	ValidFrom   sql.NullTime   `json:"valid_from"`
+	ValidTo     sql.NullTime   `json:"valid_to"`
+}
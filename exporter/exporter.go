@@ -0,0 +1,160 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	importer "github.com/mateuszkochelski/SwiftCodeDb/importer"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+// CSVHeader matches the layout of seeder/swift_codes.csv so exports can be imported again, followed by
+// the validity columns under the names the importer recognizes.
+var CSVHeader = []string{"COUNTRY ISO2 CODE", "SWIFT CODE", "CODE TYPE", "NAME", "ADDRESS", "TOWN NAME", "COUNTRY NAME", "TIME ZONE", "VALID FROM", "VALID TO"}
+
+// Writer streams banks in a single format. Close must be called to terminate the output.
+type Writer interface {
+	Write(bank models.Bank) error
+	Flush() error
+	Close() error
+}
+
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case importer.FormatCSV:
+		return newCSVWriter(w)
+	case importer.FormatJSON:
+		return newJSONWriter(w), nil
+	case importer.FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func ContentType(format string) string {
+	switch format {
+	case importer.FormatCSV:
+		return "text/csv"
+	case importer.FormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (c *csvWriter) Write(bank models.Bank) error {
+	return c.writer.Write([]string{
+		bank.CountryCode,
+		bank.SwiftCode,
+		codeType(bank.SwiftCode),
+		bank.BankName,
+		bank.Address,
+		"",
+		bank.CountryName,
+		"",
+		csvDate(bank.ValidFrom),
+		csvDate(bank.ValidTo),
+	})
+}
+
+// csvDate leaves the column empty for an open-ended validity, which the importer reads back as nil.
+func csvDate(date *models.Date) string {
+	if date == nil {
+		return ""
+	}
+	return date.String()
+}
+
+func (c *csvWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+type ndjsonWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	writer := bufio.NewWriter(w)
+	return &ndjsonWriter{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+func (n *ndjsonWriter) Write(bank models.Bank) error {
+	return n.encoder.Encode(bank)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.writer.Flush()
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.Flush()
+}
+
+type jsonWriter struct {
+	writer *bufio.Writer
+	count  int
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{writer: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) Write(bank models.Bank) error {
+	separator := ","
+	if j.count == 0 {
+		separator = "["
+	}
+	j.count++
+
+	data, err := json.Marshal(bank)
+	if err != nil {
+		return err
+	}
+	if _, err := j.writer.WriteString(separator); err != nil {
+		return err
+	}
+	_, err = j.writer.Write(data)
+	return err
+}
+
+func (j *jsonWriter) Flush() error {
+	return j.writer.Flush()
+}
+
+func (j *jsonWriter) Close() error {
+	closing := "]"
+	if j.count == 0 {
+		closing = "[]"
+	}
+	if _, err := j.writer.WriteString(closing + "\n"); err != nil {
+		return err
+	}
+	return j.Flush()
+}
+
+func codeType(swiftCode string) string {
+	if len(swiftCode) == 8 {
+		return "BIC8"
+	}
+	return "BIC11"
+}
@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	importer "github.com/mateuszkochelski/SwiftCodeDb/importer"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	"github.com/stretchr/testify/require"
+)
+
+var exportedBanks = []models.Bank{
+	{
+		Address:       "UL. PULAWSKA 15, WARSZAWA",
+		BankName:      "PKO BANK POLSKI",
+		CountryCode:   "PL",
+		CountryName:   "POLAND",
+		IsHeadquarter: true,
+		SwiftCode:     "BPKOPLPWXXX",
+	},
+	{
+		BankName:    "PKO BANK POLSKI, \"KRAKOW\"",
+		CountryCode: "PL",
+		CountryName: "POLAND",
+		SwiftCode:   "BPKOPLPWKRK",
+		ValidFrom:   &models.Date{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		ValidTo:     &models.Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	},
+}
+
+func Test_export_round_trips_through_importer(t *testing.T) {
+	for _, format := range []string{importer.FormatCSV, importer.FormatJSON, importer.FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buffer bytes.Buffer
+			writer, err := NewWriter(&buffer, format)
+			require.NoError(t, err)
+			for _, bank := range exportedBanks {
+				require.NoError(t, writer.Write(bank))
+			}
+			require.NoError(t, writer.Close())
+
+			reader, err := importer.NewReader(&buffer, format, importer.CSVOptions{})
+			require.NoError(t, err)
+			for _, expected := range exportedBanks {
+				bank, err := reader.Read()
+				require.NoError(t, err)
+				if format == importer.FormatCSV {
+					bank.IsHeadquarter = expected.IsHeadquarter
+				}
+				require.Equal(t, expected, bank)
+			}
+			_, err = reader.Read()
+			require.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func Test_csv_export_uses_seed_file_layout(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(&buffer, importer.FormatCSV)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(exportedBanks[0]))
+	require.NoError(t, writer.Close())
+
+	require.Equal(t,
+		"COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE,VALID FROM,VALID TO\n"+
+			"PL,BPKOPLPWXXX,BIC11,PKO BANK POLSKI,\"UL. PULAWSKA 15, WARSZAWA\",,POLAND,,,\n",
+		buffer.String())
+}
+
+func Test_json_export_of_nothing_is_empty_array(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(&buffer, importer.FormatJSON)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.Equal(t, "[]\n", buffer.String())
+}
+
+func Test_new_writer_rejects_unknown_format(t *testing.T) {
+	_, err := NewWriter(io.Discard, "xml")
+	require.EqualError(t, err, `unsupported format "xml"`)
+}
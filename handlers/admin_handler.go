@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	consistency "github.com/mateuszkochelski/SwiftCodeDb/consistency"
+)
+
+func (h *BankHandler) CheckConsistency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	var ignore []string
+	if value := r.URL.Query().Get("ignore"); value != "" {
+		ignore = strings.Split(value, ",")
+	}
+
+	report, err := consistency.Run(r.Context(), h.queries, ignore...)
+	if err != nil {
+		if errors.Is(err, consistency.ErrUnknownCheck) {
+			sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+			return
+		}
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
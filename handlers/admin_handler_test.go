@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	consistency "github.com/mateuszkochelski/SwiftCodeDb/consistency"
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConsistencyReportsBranchWithoutHeadquarter(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Orphan Branch",
+		SwiftCode:   "ORPHPLPWKRK",
+		CountryCode: "PL",
+		BankType:    models.BankType(false),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "ORPHPLPWKRK")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/consistency?ignore=emptyAddresses", nil)
+	resp := httptest.NewRecorder()
+	handler.CheckConsistency(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var report consistency.Report
+	err = json.NewDecoder(resp.Body).Decode(&report)
+	assert.NoError(t, err)
+	assert.Contains(t, report.BranchesWithoutHeadquarter, "ORPHPLPWKRK")
+	assert.Empty(t, report.EmptyAddresses)
+	assert.Positive(t, report.Violations)
+}
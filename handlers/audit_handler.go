@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const (
+	anonymousActor  = "anonymous"
+	requestIDHeader = "X-Request-ID"
+)
+
+type AuditEntry struct {
+	Operation db.AuditOperation `json:"operation"`
+	Before    json.RawMessage   `json:"before"`
+	After     json.RawMessage   `json:"after"`
+	Actor     string            `json:"actor"`
+	RequestID string            `json:"requestId,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+type HistoryResponse struct {
+	SwiftCode string       `json:"swiftCode"`
+	History   []AuditEntry `json:"history"`
+}
+
+func auditMetadataFromRequest(r *http.Request) store.AuditMetadata {
+	actor := anonymousActor
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		actor = principal.Name
+	}
+	return store.AuditMetadata{Actor: actor, RequestID: r.Header.Get(requestIDHeader)}
+}
+
+func (h *BankHandler) GetBankHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	swiftCode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/"), "/history")
+	logSwiftCode(r, swiftCode)
+	asOf, err := asOfParam(r)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+	// Entries from the asOf day itself are included.
+	until := asOf.AddDate(0, 0, 1)
+	rows, err := h.queries.ListBankAuditBySwiftCode(r.Context(), swiftCode)
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := HistoryResponse{SwiftCode: swiftCode, History: []AuditEntry{}}
+	for _, row := range rows {
+		if !row.CreatedAt.Before(until) {
+			continue
+		}
+		response.History = append(response.History, AuditEntry{
+			Operation: row.Operation,
+			Before:    row.BeforeData,
+			After:     row.AfterData,
+			Actor:     row.Actor,
+			RequestID: row.RequestID,
+			Timestamp: row.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
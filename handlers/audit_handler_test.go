@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAndDeleteAreRecordedInHistory(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+
+	bankJSON := `{"swiftCode": "HISTPLPWXXX", "bankName": "History Bank", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true}`
+	createReq := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", strings.NewReader(bankJSON))
+	createReq.Header.Set("X-Request-ID", "history-create")
+	createResp := httptest.NewRecorder()
+	handler.CreateBank(createResp, createReq)
+	assert.Equal(t, http.StatusCreated, createResp.Code)
+
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/HISTPLPWXXX", nil)
+	deleteReq.Header.Set("If-Match", "*")
+	deleteResp := httptest.NewRecorder()
+	handler.DeleteBank(deleteResp, deleteReq)
+	assert.Equal(t, http.StatusCreated, deleteResp.Code)
+
+	historyReq := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/HISTPLPWXXX/history", nil)
+	historyResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(historyResp, historyReq)
+	assert.Equal(t, http.StatusOK, historyResp.Code)
+
+	var response HistoryResponse
+	err := json.NewDecoder(historyResp.Body).Decode(&response)
+	assert.NoError(t, err)
+	if assert.GreaterOrEqual(t, len(response.History), 2) {
+		created := response.History[len(response.History)-2]
+		deleted := response.History[len(response.History)-1]
+		assert.Equal(t, db.AuditOperationCreate, created.Operation)
+		assert.Equal(t, "history-create", created.RequestID)
+		assert.JSONEq(t, "null", string(created.Before))
+		assert.Equal(t, db.AuditOperationDelete, deleted.Operation)
+		assert.Contains(t, string(deleted.Before), "History Bank")
+		assert.JSONEq(t, "null", string(deleted.After))
+	}
+}
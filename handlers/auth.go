@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Name string
+	Role db.ApiKeyRole
+}
+
+type principalContextKey struct{}
+
+// PrincipalFromContext returns the caller authenticated for the request, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+// Permission names the least role allowed to call a route, separately for reads (GET and HEAD) and
+// for every other method.
+type Permission struct {
+	Read  db.ApiKeyRole
+	Write db.ApiKeyRole
+}
+
+func (p Permission) required(method string) db.ApiKeyRole {
+	if method == http.MethodGet || method == http.MethodHead {
+		return p.Read
+	}
+	return p.Write
+}
+
+// ProblemResponse is an RFC 7807 problem document. Error repeats Detail for clients that read ErrorResponse.
+type ProblemResponse struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Error     string `json:"error"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+func sendProblem(w http.ResponseWriter, statusCode int, detail string, details ...interface{}) {
+	message := fmt.Sprintf(detail, details...)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ProblemResponse{
+		Type:      "about:blank",
+		Title:     http.StatusText(statusCode),
+		Status:    statusCode,
+		Detail:    message,
+		Error:     message,
+		RequestID: w.Header().Get(requestIDHeader),
+	})
+}
+
+// ErrInvalidCredentials is returned by a TokenVerifier for tokens that identify no one.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// TokenVerifier resolves the Bearer token of a request to the principal it identifies.
+// Tokens that are unknown, revoked or fail validation are reported as ErrInvalidCredentials.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (Principal, error)
+}
+
+// APIKeyVerifier accepts the static keys managed with swiftdb apikey.
+type APIKeyVerifier struct {
+	queries *db.Queries
+}
+
+func NewAPIKeyVerifier(dataBase *sql.DB) *APIKeyVerifier {
+	return &APIKeyVerifier{queries: db.New(dataBase)}
+}
+
+func (v *APIKeyVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	apiKey, err := v.queries.GetActiveApiKeyByHash(ctx, store.HashAPIKey(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Principal{}, fmt.Errorf("%w: unknown or revoked API key", ErrInvalidCredentials)
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	return Principal{Name: apiKey.Name, Role: apiKey.Role}, nil
+}
+
+// Authenticator checks the Bearer token of a request and the role it grants before the request
+// reaches its handler.
+type Authenticator struct {
+	verifier    TokenVerifier
+	publicReads bool
+}
+
+// NewAuthenticator returns an Authenticator; with publicReads set, routes open to readers are served
+// without a token.
+func NewAuthenticator(verifier TokenVerifier, publicReads bool) *Authenticator {
+	return &Authenticator{verifier: verifier, publicReads: publicReads}
+}
+
+// Require lets a request through to next only if its caller holds the role permission asks for its method.
+func (a *Authenticator) Require(permission Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		required := permission.required(r.Method)
+		if a.publicReads && required == db.ApiKeyRoleReader && r.Header.Get("Authorization") == "" {
+			next(w, r)
+			return
+		}
+
+		principal, ok := a.authenticate(w, r)
+		if !ok {
+			return
+		}
+		if !store.RoleGrants(principal.Role, required) {
+			sendProblem(w, http.StatusForbidden, "Role %q is not allowed to %s %s, %s is required", principal.Role, r.Method, r.URL.Path, required)
+			return
+		}
+
+		logPrincipal(r.Context(), principal)
+		ctx := context.WithValue(r.Context(), principalContextKey{}, principal)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func (a *Authenticator) authenticate(w http.ResponseWriter, r *http.Request) (Principal, bool) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="swift-codes"`)
+		sendProblem(w, http.StatusUnauthorized, "Missing bearer token")
+		return Principal{}, false
+	}
+
+	principal, err := a.verifier.Verify(r.Context(), token)
+	if errors.Is(err, ErrInvalidCredentials) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="swift-codes", error="invalid_token"`)
+		sendProblem(w, http.StatusUnauthorized, "%s", err.Error())
+		return Principal{}, false
+	}
+	if err != nil {
+		requestLogger(r).ErrorContext(r.Context(), "verifying bearer token failed", "error", err)
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return Principal{}, false
+	}
+	return principal, true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticatorEnforcesRolePerRoute(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	queries := db.New(dbConn)
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 10)
+	keys := make(map[db.ApiKeyRole]string)
+	for _, role := range store.APIKeyRoles {
+		name := string(role) + "-" + suffix
+		key, _, err := store.CreateAPIKey(context.Background(), queries, name, role)
+		assert.NoError(t, err)
+		defer queries.RevokeApiKey(context.Background(), name)
+		keys[role] = key
+	}
+
+	var principal Principal
+	next := func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}
+	call := func(handler http.HandlerFunc, method string, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/swift-codes", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+
+	editors := NewAuthenticator(NewAPIKeyVerifier(dbConn), true).Require(Permission{Read: db.ApiKeyRoleReader, Write: db.ApiKeyRoleEditor}, next)
+	assert.Equal(t, http.StatusNoContent, call(editors, http.MethodGet, "").Code)
+
+	missing := call(editors, http.MethodPost, "")
+	assert.Equal(t, http.StatusUnauthorized, missing.Code)
+	assert.NotEmpty(t, missing.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, call(editors, http.MethodDelete, "swk_unknown").Code)
+
+	forbidden := call(editors, http.MethodPost, keys[db.ApiKeyRoleReader])
+	assert.Equal(t, http.StatusForbidden, forbidden.Code)
+	assert.Equal(t, "application/problem+json", forbidden.Header().Get("Content-Type"))
+	assert.Contains(t, forbidden.Body.String(), `"status":403`)
+
+	assert.Equal(t, http.StatusNoContent, call(editors, http.MethodPost, keys[db.ApiKeyRoleEditor]).Code)
+	assert.Equal(t, Principal{Name: "editor-" + suffix, Role: db.ApiKeyRoleEditor}, principal)
+	assert.Equal(t, http.StatusNoContent, call(editors, http.MethodPost, keys[db.ApiKeyRoleAdmin]).Code)
+
+	admins := NewAuthenticator(NewAPIKeyVerifier(dbConn), true).Require(Permission{Read: db.ApiKeyRoleAdmin, Write: db.ApiKeyRoleAdmin}, next)
+	assert.Equal(t, http.StatusUnauthorized, call(admins, http.MethodGet, "").Code)
+	assert.Equal(t, http.StatusForbidden, call(admins, http.MethodGet, keys[db.ApiKeyRoleEditor]).Code)
+	assert.Equal(t, http.StatusNoContent, call(admins, http.MethodGet, keys[db.ApiKeyRoleAdmin]).Code)
+
+	_, err := queries.RevokeApiKey(context.Background(), "editor-"+suffix)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, call(editors, http.MethodPost, keys[db.ApiKeyRoleEditor]).Code)
+
+	private := NewAuthenticator(NewAPIKeyVerifier(dbConn), false).Require(Permission{Read: db.ApiKeyRoleReader, Write: db.ApiKeyRoleEditor}, next)
+	assert.Equal(t, http.StatusUnauthorized, call(private, http.MethodGet, "").Code)
+	assert.Equal(t, http.StatusNoContent, call(private, http.MethodGet, keys[db.ApiKeyRoleReader]).Code)
+}
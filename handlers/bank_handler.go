@@ -1,38 +1,94 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
 	models "github.com/mateuszkochelski/SwiftCodeDb/models"
 	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
 )
 
+const (
+	branchPolicyBlock   = "block"
+	branchPolicyCascade = "cascade"
+	branchPolicyOrphan  = "orphan"
+)
+
+// errRollback aborts a transaction whose failure is already described by an apiError.
+var errRollback = errors.New("operation failed, rolling back")
+
 type BankHandler struct {
-	queries *db.Queries
+	conn              *sql.DB
+	queries           *db.Queries
+	idempotencyKeyTTL time.Duration
+	cache             *lookupCache
 }
 
 func NewBankHandler(dataBase *sql.DB) *BankHandler {
-	return &BankHandler{queries: db.New(dataBase)}
+	return &BankHandler{conn: dataBase, queries: db.New(dataBase), idempotencyKeyTTL: DefaultIdempotencyKeyTTL}
+}
+
+// SetIdempotencyKeyTTL sets how long responses stored under an Idempotency-Key are replayed.
+func (h *BankHandler) SetIdempotencyKeyTTL(ttl time.Duration) {
+	h.idempotencyKeyTTL = ttl
+}
+
+// EnableCache caches up to size results of each kind of lookup for ttl. Writes through this handler
+// invalidate what they change and ListenForChanges evicts what writes made elsewhere change; ttl
+// bounds how stale lookups get should a change go unnoticed.
+func (h *BankHandler) EnableCache(size int, ttl time.Duration) {
+	h.cache = newLookupCache(size, ttl)
 }
 
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error     string `json:"error"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// apiError carries the status and message a failed operation maps to, so single and batch endpoints report alike.
+type apiError struct {
+	status  int
+	message string
+}
+
+func newAPIError(status int, errMsg string, details ...interface{}) *apiError {
+	return &apiError{status: status, message: fmt.Sprintf(errMsg, details...)}
 }
 
 type BankResponse struct {
-	Address       string        `json:"address"`
-	BankName      string        `json:"bankName"`
-	CountryISO2   string        `json:"countryISO2"`
-	CountryName   string        `json:"countryName"`
-	IsHeadquarter bool          `json:"isHeadquarter"`
-	SwiftCode     string        `json:"swiftCode"`
-	Branches      []models.Bank `json:"branches,omitempty"`
+	Address        string        `json:"address"`
+	BankName       string        `json:"bankName"`
+	CountryISO2    string        `json:"countryISO2"`
+	CountryName    string        `json:"countryName"`
+	IsHeadquarter  bool          `json:"isHeadquarter"`
+	SwiftCode      string        `json:"swiftCode"`
+	Branches       []models.Bank `json:"branches,omitempty"`
+	Headquarter    *Headquarter  `json:"headquarter,omitempty"`
+	RedirectedFrom string        `json:"redirectedFrom,omitempty"`
+	DeletedAt      *time.Time    `json:"deletedAt,omitempty"`
+}
+
+type Headquarter struct {
+	Address   string `json:"address"`
+	BankName  string `json:"bankName"`
+	SwiftCode string `json:"swiftCode"`
+}
+
+type SiblingsResponse struct {
+	SwiftCode string        `json:"swiftCode"`
+	Siblings  []models.Bank `json:"siblings"`
+}
+
+type DeleteBankResponse struct {
+	Message string   `json:"message"`
+	Deleted []string `json:"deleted"`
 }
 
 type CountryBanksResponse struct {
@@ -46,7 +102,8 @@ func sendJSONError(w http.ResponseWriter, statusCode int, errMsg string, details
 	w.WriteHeader(statusCode)
 
 	response := ErrorResponse{
-		Error: fmt.Sprintf(errMsg, details...),
+		Error:     fmt.Sprintf(errMsg, details...),
+		RequestID: w.Header().Get(requestIDHeader),
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -58,52 +115,160 @@ func (h *BankHandler) GetBanksBySwiftCode(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	var response any
 	swiftCode := strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/")
-	bankQueryResult, err := h.queries.GetBankBySwiftCodeWithCountry(r.Context(), swiftCode)
+	logSwiftCode(r, swiftCode)
+	includeDeleted, allowed := includeDeletedParam(r)
+	if !allowed {
+		sendProblem(w, http.StatusForbidden, "includeDeleted is only available to admins")
+		return
+	}
+	asOf, err := asOfParam(r)
 	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+	bankQueryResult, err := h.cache.bank(r.Context(), h.queries, db.GetBankBySwiftCodeWithCountryParams{
+		SwiftCode:      swiftCode,
+		IncludeDeleted: includeDeleted,
+		AsOf:           asOf,
+	})
+	redirectedFrom := ""
+	if errors.Is(err, sql.ErrNoRows) {
+		successor, resolveErr := store.ResolveRedirect(r.Context(), h.queries, swiftCode)
+		if errors.Is(resolveErr, store.ErrRedirectCycle) {
+			sendJSONError(w, http.StatusLoopDetected, "%s", resolveErr.Error())
+			return
+		}
+		if resolveErr != nil {
+			sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		if successor != swiftCode {
+			if r.URL.Query().Get("follow") == "false" {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Location", "/v1/swift-codes/"+successor)
+				w.WriteHeader(http.StatusMovedPermanently)
+				json.NewEncoder(w).Encode(map[string]string{"swiftCode": successor, "redirectedFrom": swiftCode})
+				return
+			}
+			redirectedFrom, swiftCode = swiftCode, successor
+			bankQueryResult, err = h.cache.bank(r.Context(), h.queries, db.GetBankBySwiftCodeWithCountryParams{
+				SwiftCode:      swiftCode,
+				IncludeDeleted: includeDeleted,
+				AsOf:           asOf,
+			})
+		}
+	}
+	if err != nil {
+		swiftCodeLookups.Inc(lookupMiss)
 		sendJSONError(w, http.StatusNotFound, "Not Found: ")
 		return
 	}
+	swiftCodeLookups.Inc(lookupHit)
 
-	bank := models.ConvertToBank(bankQueryResult)
+	response, apiErr := bankResponse(r.Context(), h.cache, h.queries, bankQueryResult, asOf, includeDeleted)
+	if apiErr != nil {
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	response.RedirectedFrom = redirectedFrom
+	body, etag, err := encodeRepresentation(response)
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch := r.Header.Get(ifNoneMatchHeader); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// bankResponse builds the representation of bank served by GetBanksBySwiftCode: a headquarter with its branches,
+// or a branch with its headquarter, as seen on asOf. Lookups go through c, which may be nil.
+func bankResponse(ctx context.Context, c *lookupCache, queries *db.Queries, row db.GetBankBySwiftCodeWithCountryRow, asOf time.Time, includeDeleted bool) (BankResponse, *apiError) {
+	bank := models.ConvertToBank(row)
+	response := BankResponse{
+		Address:       bank.Address,
+		BankName:      bank.BankName,
+		CountryISO2:   bank.CountryCode,
+		CountryName:   bank.CountryName,
+		IsHeadquarter: bank.IsHeadquarter,
+		SwiftCode:     bank.SwiftCode,
+		DeletedAt:     bank.DeletedAt,
+	}
 
 	if bank.IsHeadquarter {
-		swiftCodePrefix, ok := strings.CutSuffix(swiftCode, "XXX")
+		swiftCodePrefix, ok := strings.CutSuffix(bank.SwiftCode, "XXX")
 		if !ok {
-			sendJSONError(w, http.StatusInternalServerError, "Data inconsistency: headquarter bank must have SWIFT code ending in 'XXX'")
-			return
+			return BankResponse{}, newAPIError(http.StatusInternalServerError, "Data inconsistency: headquarter bank must have SWIFT code ending in 'XXX'")
 		}
-		sqlRegex := swiftCodePrefix + "___"
 		queryParams := db.GetBanksBranchesBySwiftCodePrefixParams{
-			SwiftCode:   sqlRegex,
-			SwiftCode_2: swiftCode,
+			BicPrefix:      swiftCodePrefix,
+			SwiftCode:      bank.SwiftCode,
+			IncludeDeleted: includeDeleted,
+			AsOf:           asOf,
 		}
 
-		banksQueryResult, err := h.queries.GetBanksBranchesBySwiftCodePrefix(r.Context(), queryParams)
+		banksQueryResult, err := c.branchesOf(ctx, queries, queryParams)
 		if err != nil && err != sql.ErrNoRows {
-			sendJSONError(w, http.StatusInternalServerError, "Internal server error")
-			return
+			return BankResponse{}, newAPIError(http.StatusInternalServerError, "Internal server error")
 		}
+		response.Branches = models.ConvertToBanks(banksQueryResult)
+		return response, nil
+	}
 
-		branches := models.ConvertToBanks(banksQueryResult)
+	if strings.HasSuffix(bank.SwiftCode, "XXX") {
+		return BankResponse{}, newAPIError(http.StatusInternalServerError, "Data inconsistency: branch bank should not have SWIFT code ending in 'XXX'")
+	}
+	headquarter, err := findHeadquarter(ctx, c, queries, bank.SwiftCode, asOf)
+	if err != nil {
+		return BankResponse{}, newAPIError(http.StatusInternalServerError, "Internal server error")
+	}
+	response.Headquarter = headquarter
+	return response, nil
+}
 
-		response = BankResponse{
-			Address:       bank.Address,
-			BankName:      bank.BankName,
-			CountryISO2:   bank.CountryCode,
-			CountryName:   bank.CountryName,
-			IsHeadquarter: bank.IsHeadquarter,
-			SwiftCode:     bank.SwiftCode,
-			Branches:      branches,
-		}
-	} else {
-		response = bank
-		_, ok := strings.CutSuffix(swiftCode, "XXX")
-		if ok {
-			http.Error(w, "Data inconsistency: branch bank should not have SWIFT code ending in 'XXX'", http.StatusInternalServerError)
-			return
+func (h *BankHandler) GetBankSiblings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	swiftCode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/"), "/siblings")
+	logSwiftCode(r, swiftCode)
+	asOf, err := asOfParam(r)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+	bank, err := h.cache.bank(r.Context(), h.queries, db.GetBankBySwiftCodeWithCountryParams{SwiftCode: swiftCode, AsOf: asOf})
+	if err != nil {
+		sendJSONError(w, http.StatusNotFound, "Not Found: ")
+		return
+	}
+
+	queryParams := db.GetBanksBranchesBySwiftCodePrefixParams{
+		BicPrefix: models.InstitutionPrefix(bank.SwiftCode),
+		SwiftCode: bank.SwiftCode,
+		AsOf:      asOf,
+	}
+	banksQueryResult, err := h.cache.branchesOf(r.Context(), h.queries, queryParams)
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := SiblingsResponse{SwiftCode: bank.SwiftCode, Siblings: []models.Bank{}}
+	for _, row := range banksQueryResult {
+		if row.BankType == db.BankTypeHeadquarter {
+			continue
 		}
+		response.Siblings = append(response.Siblings, models.ConvertToBank(row))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -111,6 +276,26 @@ func (h *BankHandler) GetBanksBySwiftCode(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(response)
 }
 
+// findHeadquarter resolves the headquarter of a branch from its 8 character institution prefix, returning nil when there is none.
+func findHeadquarter(ctx context.Context, c *lookupCache, queries *db.Queries, swiftCode string, asOf time.Time) (*Headquarter, error) {
+	row, err := c.bank(ctx, queries, db.GetBankBySwiftCodeWithCountryParams{
+		SwiftCode: models.HeadquarterSwiftCode(swiftCode),
+		AsOf:      asOf,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Headquarter{
+		Address:   row.BankAddress.String,
+		BankName:  row.BankName,
+		SwiftCode: row.SwiftCode,
+	}, nil
+}
+
 func (h *BankHandler) GetBanksByContryCode(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
@@ -118,21 +303,31 @@ func (h *BankHandler) GetBanksByContryCode(w http.ResponseWriter, r *http.Reques
 	}
 
 	countryCode := strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/country/")
-	country, err := h.queries.GetCountry(r.Context(), countryCode)
+	includeDeleted, allowed := includeDeletedParam(r)
+	if !allowed {
+		sendProblem(w, http.StatusForbidden, "includeDeleted is only available to admins")
+		return
+	}
+	asOf, err := asOfParam(r)
 	if err != nil {
-		sendJSONError(w, http.StatusNotFound, "Not found")
+		sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+		return
 	}
-
-	banks, err := h.queries.GetBanksByCountryCode(r.Context(), countryCode)
+	listing, err := h.cache.country(r.Context(), h.queries, db.GetBanksByCountryCodeParams{
+		CountryCode:    countryCode,
+		IncludeDeleted: includeDeleted,
+		AsOf:           asOf,
+	})
 	if err != nil {
 		sendJSONError(w, http.StatusNotFound, "Not found")
+		return
 	}
 
 	response := CountryBanksResponse{
-		CountryCode: country.CountryCode,
-		CountryName: country.CountryName,
+		CountryCode: listing.country.CountryCode,
+		CountryName: listing.country.CountryName,
 	}
-	for _, bank := range banks {
+	for _, bank := range listing.banks {
 		response.SwiftCodes = append(response.SwiftCodes, models.ConvertToBank(bank))
 	}
 
@@ -153,25 +348,148 @@ func (h *BankHandler) CreateBank(w http.ResponseWriter, r *http.Request) {
 		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
+	logSwiftCode(r, request.SwiftCode)
 
+	key := r.Header.Get(idempotencyKeyHeader)
+	if len(key) > maxIdempotencyKeyLength {
+		sendJSONError(w, http.StatusBadRequest, "Idempotency-Key must be at most %d characters long", maxIdempotencyKeyLength)
+		return
+	}
+	if key != "" {
+		hash, err := requestHash(r, request)
+		if err != nil {
+			sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+		claimed, err := h.claimIdempotencyKey(r.Context(), key, hash)
+		if err != nil {
+			sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+		if !claimed {
+			h.replayIdempotentResponse(w, r, key, hash)
+			return
+		}
+	}
+
+	response := map[string]string{"message": "Bank created successfully"}
+	var apiErr *apiError
+	err = store.ExecTx(r.Context(), h.conn, func(queries *db.Queries) error {
+		if apiErr = createBank(r.Context(), queries, request, auditMetadataFromRequest(r)); apiErr != nil {
+			return errRollback
+		}
+		if key != "" {
+			return completeIdempotencyKey(r.Context(), queries, key, http.StatusCreated, response)
+		}
+		return nil
+	})
+	if err != nil && apiErr == nil {
+		apiErr = newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	if apiErr != nil {
+		if key != "" {
+			h.failIdempotencyKey(r, key, apiErr)
+		}
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	h.cache.invalidate(store.Change{SwiftCode: request.SwiftCode, CountryCode: request.CountryCode})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *BankHandler) DeleteBank(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+	swiftCode := strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/")
+	logSwiftCode(r, swiftCode)
+	policy, ok := branchPolicy(r)
+	if !ok {
+		sendJSONError(w, http.StatusBadRequest, "cascade and orphan cannot be combined")
+		return
+	}
+
+	var deleted []string
+	var apiErr *apiError
+	err := store.ExecTx(r.Context(), h.conn, func(queries *db.Queries) error {
+		var bank db.GetBankBySwiftCodeWithCountryRow
+		bank, apiErr = currentBank(r.Context(), queries, swiftCode, r.Header.Get(ifMatchHeader))
+		if apiErr != nil {
+			return errRollback
+		}
+		deleted, apiErr = deleteBank(r.Context(), queries, bank, policy, auditMetadataFromRequest(r))
+		if apiErr != nil {
+			return errRollback
+		}
+		return nil
+	})
+	if apiErr != nil {
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	h.cache.invalidate(deletedChanges(deleted)...)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(DeleteBankResponse{Message: "Bank deleted successfully", Deleted: deleted})
+}
+
+func (h *BankHandler) RestoreBank(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+	swiftCode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/"), ":restore")
+	logSwiftCode(r, swiftCode)
+
+	var restored models.Bank
+	var apiErr *apiError
+	err := store.ExecTx(r.Context(), h.conn, func(queries *db.Queries) error {
+		restored, apiErr = restoreBank(r.Context(), queries, swiftCode, auditMetadataFromRequest(r))
+		if apiErr != nil {
+			return errRollback
+		}
+		return nil
+	})
+	if apiErr != nil {
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	h.cache.invalidate(store.Change{SwiftCode: restored.SwiftCode, CountryCode: restored.CountryCode})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(restored)
+}
+
+func createBank(ctx context.Context, queries *db.Queries, request models.Bank, meta store.AuditMetadata) *apiError {
 	_, ok := strings.CutSuffix(request.SwiftCode, "XXX")
 	if request.IsHeadquarter && !ok {
-		sendJSONError(w, http.StatusUnprocessableEntity, "Headquarter swift code should end with XXX")
-		return
+		return newAPIError(http.StatusUnprocessableEntity, "Headquarter swift code should end with XXX")
 	}
 	if !request.IsHeadquarter && ok {
-		sendJSONError(w, http.StatusUnprocessableEntity, "Branch swift code shouldnt end with XXX")
-		return
+		return newAPIError(http.StatusUnprocessableEntity, "Branch swift code shouldnt end with XXX")
 	}
 
 	country := db.CreateCountryParams{
 		CountryCode: request.CountryCode,
 		CountryName: request.CountryName,
 	}
-	err = store.InsertCountryWithValidation(h.queries, country)
+	err := store.InsertCountryWithValidation(queries, country)
 	if err != nil {
-		sendJSONError(w, http.StatusUnprocessableEntity, "Error during country insertion: %s", err.Error())
-		return
+		return newAPIError(http.StatusUnprocessableEntity, "Error during country insertion: %s", err.Error())
 	}
 
 	bank := db.CreateBankParams{
@@ -180,49 +498,200 @@ func (h *BankHandler) CreateBank(w http.ResponseWriter, r *http.Request) {
 		BankAddress: sql.NullString{String: request.Address, Valid: len(request.Address) != 0},
 		CountryCode: request.CountryCode,
 		BankType:    models.BankType(request.IsHeadquarter),
+		ValidFrom:   request.ValidFrom.NullTime(),
+		ValidTo:     request.ValidTo.NullTime(),
 	}
-	err = store.InsertBankWithValidation(h.queries, bank)
+	err = store.InsertBankWithValidation(ctx, queries, bank, meta)
 	if err != nil {
-		sendJSONError(w, http.StatusUnprocessableEntity, "Error during bank insertion: %s,%s", err.Error(), bank.CountryCode)
-		return
+		return newAPIError(http.StatusUnprocessableEntity, "Error during bank insertion: %s,%s", err.Error(), bank.CountryCode)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Bank created successfully"})
+	err = store.RecordAudit(ctx, queries, meta, db.AuditOperationCreate, request.SwiftCode, nil, &request)
+	if err != nil {
+		return newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	err = store.NotifyChanges(ctx, queries, store.Change{SwiftCode: request.SwiftCode, CountryCode: request.CountryCode})
+	if err != nil {
+		return newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	return nil
 }
 
-func (h *BankHandler) DeleteBank(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
-		return
+// deleteBank removes bank, failing when its row changed since it was loaded, and for a headquarter applies policy
+// to its branches. It returns every removed SWIFT code.
+func deleteBank(ctx context.Context, queries *db.Queries, bank db.GetBankBySwiftCodeWithCountryRow, policy string, meta store.AuditMetadata) ([]string, *apiError) {
+	swiftCode := bank.SwiftCode
+	_, err := queries.DeleteBank(ctx, db.DeleteBankParams{ID: bank.ID, Version: bank.Version})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, newAPIError(http.StatusPreconditionFailed, "Bank was modified concurrently")
+	}
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	before := models.ConvertToBank(bank)
+	if err := store.RecordAudit(ctx, queries, meta, db.AuditOperationDelete, swiftCode, &before, nil); err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
 	}
-	swiftCode := strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/")
 
-	_, err := h.queries.DeleteBankBySwiftCode(r.Context(), swiftCode)
+	deleted := []string{swiftCode}
+	if bank.BankType == db.BankTypeHeadquarter && policy != branchPolicyOrphan {
+		bicPrefix := models.InstitutionPrefix(swiftCode)
+
+		if policy == branchPolicyCascade {
+			branches, err := queries.DeleteBanksBranchesBySwiftCodePrefix(ctx, db.DeleteBanksBranchesBySwiftCodePrefixParams{
+				BicPrefix: bicPrefix,
+				SwiftCode: swiftCode,
+			})
+			if err != nil {
+				return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+			}
+			for _, branch := range branches {
+				before := models.ConvertToBank(branch)
+				if err := store.RecordAudit(ctx, queries, meta, db.AuditOperationDelete, branch.SwiftCode, &before, nil); err != nil {
+					return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+				}
+				deleted = append(deleted, branch.SwiftCode)
+			}
+		} else {
+			// Branches dated in the past or future count too, as the delete would leave them without a headquarter.
+			branches, err := queries.CountBanksBranchesBySwiftCodePrefix(ctx, db.CountBanksBranchesBySwiftCodePrefixParams{
+				BicPrefix: bicPrefix,
+				SwiftCode: swiftCode,
+			})
+			if err != nil {
+				return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+			}
+			if branches > 0 {
+				return nil, newAPIError(http.StatusConflict, "Headquarter has %d branches, pass cascade=true to delete them as well", branches)
+			}
+		}
+	}
+
+	// The past and future validity windows of the code go as well. now() is fixed for the transaction, so they share
+	// the deleted_at of the current row and restoreBank brings them all back.
+	rows, err := queries.DeleteBankBySwiftCode(ctx, swiftCode)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			sendJSONError(w, http.StatusNotFound, "Bank not found")
-			return
+		return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	for _, row := range rows {
+		before := models.ConvertToBank(row)
+		before.DeletedAt = nil
+		if err := store.RecordAudit(ctx, queries, meta, db.AuditOperationDelete, swiftCode, &before, nil); err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
 		}
+	}
+	if err := store.NotifyChanges(ctx, queries, deletedChanges(deleted)...); err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	return deleted, nil
+}
 
-		sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
-		return
+// restoreBank undeletes the rows of swiftCode removed by its most recent delete, refusing while a live bank holds
+// the code. It returns the row valid today, or the first restored one when none is.
+func restoreBank(ctx context.Context, queries *db.Queries, swiftCode string, meta store.AuditMetadata) (models.Bank, *apiError) {
+	_, err := queries.GetBankBySwiftCodeWithCountry(ctx, db.GetBankBySwiftCodeWithCountryParams{SwiftCode: swiftCode, AsOf: time.Now()})
+	if err == nil {
+		return models.Bank{}, newAPIError(http.StatusConflict, "Bank %s is not deleted", swiftCode)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.Bank{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Bank deleted successfully"})
+	rows, err := queries.RestoreBankBySwiftCode(ctx, swiftCode)
+	if err != nil {
+		return models.Bank{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	if len(rows) == 0 {
+		return models.Bank{}, newAPIError(http.StatusNotFound, "No deleted bank with swift code %s", swiftCode)
+	}
+
+	restored := models.ConvertToBank(rows[0])
+	now := time.Now()
+	for _, row := range rows {
+		after := models.ConvertToBank(row)
+		if err := store.RecordAudit(ctx, queries, meta, db.AuditOperationRestore, swiftCode, nil, &after); err != nil {
+			return models.Bank{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+		}
+		if err := store.NotifyChanges(ctx, queries, store.Change{SwiftCode: swiftCode, CountryCode: after.CountryCode}); err != nil {
+			return models.Bank{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+		}
+		if validOn(row, now) {
+			restored = after
+		}
+	}
+	return restored, nil
+}
+
+// validOn reports whether row's validity window covers day, the way the lookup queries compare it.
+func validOn(row db.Bank, day time.Time) bool {
+	return (!row.ValidFrom.Valid || !row.ValidFrom.Time.After(day)) && (!row.ValidTo.Valid || row.ValidTo.Time.After(day))
+}
+
+// asOfParam reads the day a lookup should see the directory as of, defaulting to today.
+func asOfParam(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("asOf")
+	if value == "" {
+		return time.Now(), nil
+	}
+	date, err := models.ParseDate(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date.Time, nil
+}
+
+// includeDeletedParam reads whether deleted banks should be shown. Only admins may see them, so the
+// second result is false when anyone else asks.
+func includeDeletedParam(r *http.Request) (bool, bool) {
+	if r.URL.Query().Get("includeDeleted") != "true" {
+		return false, true
+	}
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok || !store.RoleGrants(principal.Role, db.ApiKeyRoleAdmin) {
+		return false, false
+	}
+	return true, true
+}
+
+func branchPolicy(r *http.Request) (string, bool) {
+	cascade := r.URL.Query().Get("cascade") == "true"
+	orphan := r.URL.Query().Get("orphan") == "true"
+	switch {
+	case cascade && orphan:
+		return "", false
+	case cascade:
+		return branchPolicyCascade, true
+	case orphan:
+		return branchPolicyOrphan, true
+	}
+	return branchPolicyBlock, true
 }
 
 func (h *BankHandler) HandleSwiftCodes(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
+		if strings.HasSuffix(r.URL.Path, "/siblings") {
+			h.GetBankSiblings(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/history") {
+			h.GetBankHistory(w, r)
+			return
+		}
 		h.GetBanksBySwiftCode(w, r)
+	case http.MethodPost:
+		if strings.HasSuffix(r.URL.Path, ":restore") {
+			h.RestoreBank(w, r)
+			return
+		}
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	case http.MethodPut:
+		h.UpdateBank(w, r)
+	case http.MethodPatch:
+		h.PatchBank(w, r)
 	case http.MethodDelete:
 		h.DeleteBank(w, r)
 	default:
 		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
-
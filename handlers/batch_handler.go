@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const (
+	maxBatchSize        = 1000
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "bestEffort"
+)
+
+type BatchGetRequest struct {
+	SwiftCodes []string `json:"swiftCodes"`
+}
+
+type BatchGetResponse struct {
+	Banks    []models.Bank `json:"banks"`
+	NotFound []string      `json:"notFound"`
+	Invalid  []string      `json:"invalid"`
+}
+
+type BatchCreateRequest struct {
+	Banks []models.Bank `json:"banks"`
+}
+
+type BatchDeleteRequest struct {
+	SwiftCodes []string `json:"swiftCodes"`
+}
+
+type BatchItemResult struct {
+	SwiftCode string `json:"swiftCode"`
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+type BatchWriteResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+func (h *BankHandler) BatchGetBanks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	asOf, err := asOfParam(r)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+
+	var request BatchGetRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if len(request.SwiftCodes) == 0 {
+		sendJSONError(w, http.StatusBadRequest, "swiftCodes must not be empty")
+		return
+	}
+	if len(request.SwiftCodes) > maxBatchSize {
+		sendJSONError(w, http.StatusRequestEntityTooLarge, "At most %d swift codes can be requested at once", maxBatchSize)
+		return
+	}
+
+	response := BatchGetResponse{Banks: []models.Bank{}, NotFound: []string{}, Invalid: []string{}}
+	var validCodes []string
+	seen := make(map[string]bool)
+	for _, code := range request.SwiftCodes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		if !models.IsValidSwiftCode(code) {
+			response.Invalid = append(response.Invalid, code)
+			continue
+		}
+		validCodes = append(validCodes, code)
+	}
+
+	if len(validCodes) > 0 {
+		rows, err := h.queries.GetBanksBySwiftCodes(r.Context(), db.GetBanksBySwiftCodesParams{SwiftCodes: validCodes, AsOf: asOf})
+		if err != nil {
+			sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		found := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			if found[row.SwiftCode] {
+				continue
+			}
+			found[row.SwiftCode] = true
+			response.Banks = append(response.Banks, models.ConvertToBank(row))
+		}
+		for _, code := range validCodes {
+			if !found[code] {
+				response.NotFound = append(response.NotFound, code)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *BankHandler) BatchCreateBanks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	mode, ok := batchMode(r)
+	if !ok {
+		sendJSONError(w, http.StatusBadRequest, "mode must be %s or %s", batchModeAtomic, batchModeBestEffort)
+		return
+	}
+
+	var request BatchCreateRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if !validBatchSize(w, len(request.Banks)) {
+		return
+	}
+
+	swiftCodes := make([]string, len(request.Banks))
+	for i, bank := range request.Banks {
+		swiftCodes[i] = bank.SwiftCode
+	}
+
+	response, err := h.runBatch(r.Context(), mode, swiftCodes, http.StatusCreated, func(queries *db.Queries, i int) *apiError {
+		return createBank(r.Context(), queries, request.Banks[i], auditMetadataFromRequest(r))
+	})
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	for i, bank := range request.Banks {
+		if applied(response.Results[i]) {
+			h.cache.invalidate(store.Change{SwiftCode: bank.SwiftCode, CountryCode: bank.CountryCode})
+		}
+	}
+
+	sendBatchWriteResponse(w, response)
+}
+
+func (h *BankHandler) BatchDeleteBanks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	mode, ok := batchMode(r)
+	if !ok {
+		sendJSONError(w, http.StatusBadRequest, "mode must be %s or %s", batchModeAtomic, batchModeBestEffort)
+		return
+	}
+
+	policy, ok := branchPolicy(r)
+	if !ok {
+		sendJSONError(w, http.StatusBadRequest, "cascade and orphan cannot be combined")
+		return
+	}
+
+	var request BatchDeleteRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if !validBatchSize(w, len(request.SwiftCodes)) {
+		return
+	}
+
+	// Items take no If-Match precondition: each deletes the bank live when it runs, though still fails with 412 when
+	// that row changes between being loaded and deleted.
+	deleted := make([][]string, len(request.SwiftCodes))
+	response, err := h.runBatch(r.Context(), mode, request.SwiftCodes, http.StatusOK, func(queries *db.Queries, i int) *apiError {
+		bank, apiErr := liveBank(r.Context(), queries, request.SwiftCodes[i])
+		if apiErr != nil {
+			return apiErr
+		}
+		deleted[i], apiErr = deleteBank(r.Context(), queries, bank, policy, auditMetadataFromRequest(r))
+		return apiErr
+	})
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	for i, removed := range deleted {
+		if applied(response.Results[i]) {
+			h.cache.invalidate(deletedChanges(removed)...)
+		}
+	}
+
+	sendBatchWriteResponse(w, response)
+}
+
+// runBatch applies op to every item, either all inside one transaction or each in a transaction of its own.
+// In atomic mode the first failure rolls back the batch and the remaining items are reported as not applied.
+func (h *BankHandler) runBatch(ctx context.Context, mode string, swiftCodes []string, successStatus int, op func(*db.Queries, int) *apiError) (BatchWriteResponse, error) {
+	response := BatchWriteResponse{Mode: mode, Results: make([]BatchItemResult, len(swiftCodes))}
+	for i, swiftCode := range swiftCodes {
+		response.Results[i] = BatchItemResult{SwiftCode: swiftCode, Status: successStatus}
+	}
+
+	if mode == batchModeAtomic {
+		failedAt := -1
+		err := store.ExecTx(ctx, h.conn, func(queries *db.Queries) error {
+			for i := range swiftCodes {
+				if apiErr := op(queries, i); apiErr != nil {
+					response.Results[i].Status = apiErr.status
+					response.Results[i].Error = apiErr.message
+					failedAt = i
+					return errRollback
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errRollback) {
+			return BatchWriteResponse{}, err
+		}
+		if failedAt >= 0 {
+			for i := range response.Results {
+				if i != failedAt {
+					response.Results[i].Status = http.StatusFailedDependency
+					response.Results[i].Error = "Not applied: another item in the batch failed"
+				}
+			}
+			response.Failed = len(swiftCodes)
+			return response, nil
+		}
+		response.Succeeded = len(swiftCodes)
+		return response, nil
+	}
+
+	for i := range swiftCodes {
+		var apiErr *apiError
+		err := store.ExecTx(ctx, h.conn, func(queries *db.Queries) error {
+			if apiErr = op(queries, i); apiErr != nil {
+				return errRollback
+			}
+			return nil
+		})
+		if err != nil && apiErr == nil {
+			apiErr = newAPIError(http.StatusInternalServerError, "Internal server error")
+		}
+		if apiErr != nil {
+			response.Results[i].Status = apiErr.status
+			response.Results[i].Error = apiErr.message
+			response.Failed++
+			continue
+		}
+		response.Succeeded++
+	}
+	return response, nil
+}
+
+// applied reports whether the item's write was committed, so its cached lookups are stale.
+func applied(result BatchItemResult) bool {
+	return result.Status >= 200 && result.Status < 300
+}
+
+func batchMode(r *http.Request) (string, bool) {
+	mode := r.URL.Query().Get("mode")
+	switch mode {
+	case "":
+		return batchModeAtomic, true
+	case batchModeAtomic, batchModeBestEffort:
+		return mode, true
+	}
+	return "", false
+}
+
+func validBatchSize(w http.ResponseWriter, size int) bool {
+	if size == 0 {
+		sendJSONError(w, http.StatusBadRequest, "Batch must not be empty")
+		return false
+	}
+	if size > maxBatchSize {
+		sendJSONError(w, http.StatusRequestEntityTooLarge, "At most %d items can be sent at once", maxBatchSize)
+		return false
+	}
+	return true
+}
+
+func sendBatchWriteResponse(w http.ResponseWriter, response BatchWriteResponse) {
+	status := http.StatusOK
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
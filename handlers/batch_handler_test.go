@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"database/sql"
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+	"time"
+)
+
+func TestBatchGetBanksSplitsFoundNotFoundAndInvalid(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Batch Bank",
+		SwiftCode:   "BATCHPLPXXX",
+		CountryCode: "PL",
+		BankType:    models.BankType(true),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "BATCHPLPXXX")
+
+	body := `{"swiftCodes": ["BATCHPLPXXX", "batchplpxxx", "NOPENOPEXXX", "SHORT"]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes:batchGet", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	handler.BatchGetBanks(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response BatchGetResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Banks, 1)
+	assert.Equal(t, "BATCHPLPXXX", response.Banks[0].SwiftCode)
+	assert.Equal(t, []string{"NOPENOPEXXX"}, response.NotFound)
+	assert.Equal(t, []string{"SHORT"}, response.Invalid)
+}
+
+func TestBatchGetBanksRejectsTooManyCodes(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+
+	codes := make([]string, maxBatchSize+1)
+	for i := range codes {
+		codes[i] = "AAAAPLPWXXX"
+	}
+	body, err := json.Marshal(BatchGetRequest{SwiftCodes: codes})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes:batchGet", bytes.NewReader(body))
+	resp := httptest.NewRecorder()
+	handler.BatchGetBanks(resp, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+}
+
+func TestBatchCreateAtomicRollsBackWholeBatchOnFailure(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	handler.EnableCache(100, time.Minute)
+	cached := bankKey{swiftCode: "ATOMPLPWXXX", asOf: time.Now().Format(time.DateOnly)}
+	handler.cache.banks.Add(cached, db.GetBankBySwiftCodeWithCountryRow{})
+
+	body := `{"banks": [
+		{"swiftCode": "ATOMPLPWXXX", "bankName": "Atomic", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true},
+		{"swiftCode": "ATOMPLPW123", "bankName": "Atomic", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes:batchCreate", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	handler.BatchCreateBanks(resp, req)
+	assert.Equal(t, http.StatusMultiStatus, resp.Code)
+
+	var response BatchWriteResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, response.Succeeded)
+	assert.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Results[1].Status)
+
+	_, err = db.New(dbConn).GetBankBySwiftCodeWithCountry(context.Background(), db.GetBankBySwiftCodeWithCountryParams{SwiftCode: "ATOMPLPWXXX", AsOf: time.Now()})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, ok := handler.cache.banks.Get(cached)
+	assert.True(t, ok, "rolled back items leave cached lookups alone")
+}
+
+func TestBatchCreateAndDeleteBestEffortReportPerItemStatus(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+
+	body := `{"banks": [
+		{"swiftCode": "BESTPLPWXXX", "bankName": "Best", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true},
+		{"swiftCode": "BESTPLPWXXX", "bankName": "Best", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes:batchCreate?mode=bestEffort", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	handler.BatchCreateBanks(resp, req)
+	assert.Equal(t, http.StatusMultiStatus, resp.Code)
+
+	var createResponse BatchWriteResponse
+	err := json.NewDecoder(resp.Body).Decode(&createResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, createResponse.Succeeded)
+	assert.Equal(t, http.StatusCreated, createResponse.Results[0].Status)
+	assert.Equal(t, http.StatusUnprocessableEntity, createResponse.Results[1].Status)
+
+	body = `{"swiftCodes": ["BESTPLPWXXX", "BESTPLPWXXX"]}`
+	req = httptest.NewRequest(http.MethodPost, "/v1/swift-codes:batchDelete?mode=bestEffort", strings.NewReader(body))
+	resp = httptest.NewRecorder()
+	handler.BatchDeleteBanks(resp, req)
+
+	var deleteResponse BatchWriteResponse
+	err = json.NewDecoder(resp.Body).Decode(&deleteResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, deleteResponse.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, deleteResponse.Results[1].Status)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+
+	metrics "github.com/mateuszkochelski/SwiftCodeDb/metrics"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	// listenerPingInterval bounds how long a silently dropped connection goes unnoticed.
+	listenerPingInterval = 90 * time.Second
+)
+
+var changeNotifications = metrics.Default.NewCounterVec("cache_change_notifications_total", "Change notifications received on the swift_changes channel, by whether they could be applied.", "result")
+
+// ListenForChanges keeps the cache in step with writes made through other replicas or the seeder,
+// evicting what they announce on the swift_changes channel until ctx is done. Notifications sent
+// while the connection is down are lost, so the whole cache is dropped whenever it is re-established.
+func (h *BankHandler) ListenForChanges(ctx context.Context, dataSource string) error {
+	listener := pq.NewListener(dataSource, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			slog.Warn("lost connection listening for changes", "channel", store.SwiftChangesChannel, "error", err)
+		case pq.ListenerEventConnectionAttemptFailed:
+			slog.Warn("cannot connect to listen for changes", "channel", store.SwiftChangesChannel, "error", err)
+		case pq.ListenerEventReconnected:
+			slog.Info("listening for changes again, dropping cached lookups", "channel", store.SwiftChangesChannel)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(store.SwiftChangesChannel); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			if notification == nil {
+				h.cache.invalidate(store.Change{All: true})
+				continue
+			}
+			change, err := store.ParseChange(notification.Extra)
+			if err != nil {
+				slog.Warn("ignoring malformed change notification", "payload", notification.Extra, "error", err)
+				changeNotifications.Inc("invalid")
+				continue
+			}
+			h.cache.invalidate(change)
+			changeNotifications.Inc("applied")
+		case <-ticker.C:
+			go listener.Ping()
+		}
+	}
+}
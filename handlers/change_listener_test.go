@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritesEvictCachedLookupsOnOtherReplicas(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	writer := NewBankHandler(dbConn)
+	replica := NewBankHandler(dbConn)
+	replica.EnableCache(100, time.Minute)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Replicated Bank",
+		SwiftCode:   "REPLPLPWXXX",
+		CountryCode: "PL",
+		BankType:    models.BankType(true),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "REPLPLPWXXX")
+
+	getResp := httptest.NewRecorder()
+	replica.HandleSwiftCodes(getResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/REPLPLPWXXX", nil))
+	assert.Equal(t, http.StatusOK, getResp.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go replica.ListenForChanges(ctx, dbSource)
+
+	// Notifications sent before LISTEN takes effect are lost, so wait until one gets through.
+	probe := bankKey{swiftCode: "PROBPLPWXXX"}
+	replica.cache.banks.Add(probe, db.GetBankBySwiftCodeWithCountryRow{})
+	assert.Eventually(t, func() bool {
+		store.NotifyChanges(context.Background(), queries, store.Change{SwiftCode: probe.swiftCode})
+		_, cached := replica.cache.banks.Get(probe)
+		return !cached
+	}, 5*time.Second, 50*time.Millisecond)
+
+	deleteResp := httptest.NewRecorder()
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/REPLPLPWXXX", nil)
+	deleteReq.Header.Set("If-Match", "*")
+	writer.HandleSwiftCodes(deleteResp, deleteReq)
+	assert.Equal(t, http.StatusCreated, deleteResp.Code)
+
+	assert.Eventually(t, func() bool {
+		getResp := httptest.NewRecorder()
+		replica.HandleSwiftCodes(getResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/REPLPLPWXXX", nil))
+		return getResp.Code == http.StatusNotFound
+	}, 5*time.Second, 50*time.Millisecond)
+}
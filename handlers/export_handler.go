@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	exporter "github.com/mateuszkochelski/SwiftCodeDb/exporter"
+	importer "github.com/mateuszkochelski/SwiftCodeDb/importer"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+const exportPageSize = 500
+
+func (h *BankHandler) ExportBanks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importer.FormatJSON
+	}
+	if format != importer.FormatCSV && format != importer.FormatJSON && format != importer.FormatNDJSON {
+		sendJSONError(w, http.StatusBadRequest, "Unsupported format %q, use csv, ndjson or json", format)
+		return
+	}
+
+	asOf, err := asOfParam(r)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+
+	countryCode := strings.ToUpper(r.URL.Query().Get("country"))
+	if countryCode != "" {
+		_, err := h.queries.GetCountry(r.Context(), countryCode)
+		if errors.Is(err, sql.ErrNoRows) {
+			sendJSONError(w, http.StatusNotFound, "Country not found")
+			return
+		}
+		if err != nil {
+			sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+	}
+
+	params := db.ListBanksPageParams{
+		CountryCode: sql.NullString{String: countryCode, Valid: countryCode != ""},
+		AsOf:        asOf,
+		PageSize:    exportPageSize,
+	}
+	page, err := h.queries.ListBanksPage(r.Context(), params)
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", exporter.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="swift_codes.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	logger := requestLogger(r).With("format", format)
+	writer, err := exporter.NewWriter(w, format)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "export failed", "error", err)
+		return
+	}
+	controller := http.NewResponseController(w)
+
+	for len(page) > 0 {
+		for _, row := range page {
+			if err := writer.Write(models.ConvertToBank(row)); err != nil {
+				logger.ErrorContext(r.Context(), "export failed", "error", err)
+				return
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			logger.ErrorContext(r.Context(), "export failed", "error", err)
+			return
+		}
+		controller.Flush()
+
+		if len(page) < exportPageSize {
+			break
+		}
+		params.AfterID = page[len(page)-1].ID
+		page, err = h.queries.ListBanksPage(r.Context(), params)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "export failed", "error", err)
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		logger.ErrorContext(r.Context(), "export failed", "error", err)
+	}
+}
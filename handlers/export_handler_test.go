@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	importer "github.com/mateuszkochelski/SwiftCodeDb/importer"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportBanksByCountryRoundTrip(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "EX", CountryName: "EXPORTLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Export Bank",
+		SwiftCode:   "EXPORTEXXXX",
+		BankAddress: sql.NullString{String: "Export Street, 1", Valid: true},
+		CountryCode: "EX",
+		BankType:    models.BankType(true),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "EXPORTEXXXX")
+
+	for _, format := range []string{"csv", "json", "ndjson"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/export?format="+format+"&country=EX", nil)
+		resp := httptest.NewRecorder()
+		handler.ExportBanks(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		reader, err := importer.NewReader(resp.Body, format, importer.CSVOptions{})
+		assert.NoError(t, err)
+		bank, err := reader.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, "EXPORTEXXXX", bank.SwiftCode)
+		assert.Equal(t, "Export Street, 1", bank.Address)
+		assert.Equal(t, "EXPORTLAND", bank.CountryName)
+	}
+}
+
+func TestExportBanksRejectsUnknownFormat(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/export?format=xml", nil)
+	resp := httptest.NewRecorder()
+	handler.ExportBanks(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+	// idempotencyKeyLease is how long a claimed key stays in progress. A claim older than that belongs to a
+	// request that crashed or was cut off before releasing it, so a retry may take the key over.
+	idempotencyKeyLease = time.Minute
+)
+
+// requestHash fingerprints a write so a reused Idempotency-Key can be told apart from a genuine retry.
+// The decoded payload is re-encoded, which makes the hash insensitive to whitespace and key order,
+// and the caller is included so one API key holder never receives another's stored response.
+func requestHash(r *http.Request, payload any) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		hash.Write([]byte(principal.Name + "\n"))
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// claimIdempotencyKey reserves key for the current request. It reports false when the key is already
+// held by an unexpired request, or by one still in progress within its lease, in which case the stored
+// outcome has to be replayed instead.
+func (h *BankHandler) claimIdempotencyKey(ctx context.Context, key string, hash string) (bool, error) {
+	now := time.Now()
+	_, err := h.queries.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   now.Add(h.idempotencyKeyTTL),
+		StaleBefore: now.Add(-idempotencyKeyLease),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// replayIdempotentResponse answers a request whose key was claimed earlier with the response stored for it.
+func (h *BankHandler) replayIdempotentResponse(w http.ResponseWriter, r *http.Request, key string, hash string) {
+	stored, err := h.queries.GetIdempotencyKey(r.Context(), key)
+	if errors.Is(err, sql.ErrNoRows) {
+		sendJSONError(w, http.StatusConflict, "The request with this Idempotency-Key did not complete, retry it")
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if stored.RequestHash != hash {
+		sendJSONError(w, http.StatusUnprocessableEntity, "Idempotency-Key has already been used with a different payload")
+		return
+	}
+	if stored.StatusCode == 0 {
+		sendJSONError(w, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(int(stored.StatusCode))
+	w.Write(stored.ResponseBody)
+}
+
+// completeIdempotencyKey stores the response sent for key so that retries receive it verbatim.
+func completeIdempotencyKey(ctx context.Context, queries *db.Queries, key string, status int, response any) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	return queries.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		Key:          key,
+		StatusCode:   int32(status),
+		ResponseBody: append(body, '\n'),
+	})
+}
+
+// failIdempotencyKey records a rejected request. Server errors release the key instead, as a retry may succeed,
+// and so does a failure to record the rejection, so that the key is not left in progress until its lease ends.
+// Both run even when the client has gone away, which is what cancelled the request in the first place.
+func (h *BankHandler) failIdempotencyKey(r *http.Request, key string, apiErr *apiError) {
+	ctx := context.WithoutCancel(r.Context())
+	logger := requestLogger(r).With("idempotency_key", key)
+	if apiErr.status < http.StatusInternalServerError {
+		err := completeIdempotencyKey(ctx, h.queries, key, apiErr.status, ErrorResponse{Error: apiErr.message})
+		if err == nil {
+			return
+		}
+		logger.WarnContext(ctx, "cannot record rejected request, releasing idempotency key", "error", err)
+	}
+	if err := h.queries.ReleaseIdempotencyKey(ctx, key); err != nil {
+		logger.ErrorContext(ctx, "cannot release idempotency key, it stays in progress until its lease ends", "error", err)
+	}
+}
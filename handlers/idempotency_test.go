@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeyReplaysCreateAndRejectsDifferentPayload(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "IDEMPLPWXXX")
+
+	key := "create-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		resp := httptest.NewRecorder()
+		handler.CreateBank(resp, req)
+		return resp
+	}
+	body := `{"address": "IDEM STREET", "bankName": "Idempotent Bank", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true, "swiftCode": "IDEMPLPWXXX"}`
+
+	first := create(body)
+	assert.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+
+	retry := create(body)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+
+	different := create(strings.Replace(body, "IDEM STREET", "OTHER STREET", 1))
+	assert.Equal(t, http.StatusUnprocessableEntity, different.Code)
+
+	duplicateReq := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", strings.NewReader(body))
+	duplicateResp := httptest.NewRecorder()
+	handler.CreateBank(duplicateResp, duplicateReq)
+	assert.Equal(t, http.StatusUnprocessableEntity, duplicateResp.Code)
+}
+
+func TestFailedIdempotencyKeyWritesAreLogged(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	conn, err := sql.Open("postgres", "postgresql://unused")
+	assert.NoError(t, err)
+	conn.Close()
+	handler := NewBankHandler(conn)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", nil)
+	req.Header.Set("X-Request-ID", "idem-id-1")
+	handler.failIdempotencyKey(req, "key-1", newAPIError(http.StatusUnprocessableEntity, "invalid"))
+
+	var messages []string
+	for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, "idem-id-1", entry["request_id"])
+		assert.Equal(t, "key-1", entry["idempotency_key"])
+		messages = append(messages, entry["msg"].(string))
+	}
+	assert.Equal(t, []string{
+		"cannot record rejected request, releasing idempotency key",
+		"cannot release idempotency key, it stays in progress until its lease ends",
+	}, messages)
+}
+
+func TestAbandonedIdempotencyKeyIsReclaimedAfterItsLease(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "LEASPLPWXXX")
+
+	body := `{"bankName": "Lease Bank", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true, "swiftCode": "LEASPLPWXXX"}`
+	key := "lease-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	create := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		resp := httptest.NewRecorder()
+		handler.CreateBank(resp, req)
+		return resp
+	}
+
+	// A request that claimed the key and then died never completes or releases it.
+	var bank models.Bank
+	assert.NoError(t, json.Unmarshal([]byte(body), &bank))
+	hash, err := requestHash(httptest.NewRequest(http.MethodPost, "/v1/swift-codes", nil), bank)
+	assert.NoError(t, err)
+	claimed, err := handler.claimIdempotencyKey(context.Background(), key, hash)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, http.StatusConflict, create().Code, "the claim is still within its lease")
+
+	_, err = dbConn.Exec("UPDATE idempotency_keys SET created_at = created_at - $2::interval WHERE key = $1", key, "2 minutes")
+	assert.NoError(t, err)
+	resp := create()
+	assert.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+}
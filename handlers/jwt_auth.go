@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	jwt "github.com/mateuszkochelski/SwiftCodeDb/jwt"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+// JWTVerifier accepts tokens issued by an identity provider. The caller's role is taken from the
+// values of roleClaim, mapped through roles; without a mapping the values must name roles directly.
+// When several values map to roles, the most privileged one wins.
+type JWTVerifier struct {
+	validator *jwt.Validator
+	roleClaim string
+	roles     map[string]db.ApiKeyRole
+}
+
+func NewJWTVerifier(validator *jwt.Validator, roleClaim string, roles map[string]db.ApiKeyRole) *JWTVerifier {
+	return &JWTVerifier{validator: validator, roleClaim: roleClaim, roles: roles}
+}
+
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	claims, err := v.validator.Validate(ctx, token)
+	if err != nil {
+		// A token naming a key the issuer cannot be asked about is still unverifiable, so it is rejected
+		// like any other; the failed fetch is only of interest to operators.
+		var keyErr *jwt.KeySetError
+		if errors.As(err, &keyErr) {
+			slog.WarnContext(ctx, "refreshing JWT key set failed", "error", keyErr)
+		}
+		return Principal{}, fmt.Errorf("%w: %s", ErrInvalidCredentials, err.Error())
+	}
+
+	principal := Principal{Name: claims.Subject()}
+	for _, value := range claims.Strings(v.roleClaim) {
+		role := db.ApiKeyRole(value)
+		if v.roles != nil {
+			role = v.roles[value]
+		}
+		if store.RoleGrants(role, principal.Role) {
+			principal.Role = role
+		}
+	}
+	return principal, nil
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	jwt "github.com/mateuszkochelski/SwiftCodeDb/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTVerifierMapsClaimsToRoles(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	encode := base64.RawURLEncoding.EncodeToString
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC", "kid": "test", "crv": "P-256",
+		"x": encode(key.X.FillBytes(make([]byte, 32))), "y": encode(key.Y.FillBytes(make([]byte, 32))),
+	}}})
+	keys, err := jwt.ParseKeySet(jwks)
+	assert.NoError(t, err)
+	validator := jwt.NewValidator(keys, "https://idp.example", "swift-codes")
+
+	token := func(groups ...string) string {
+		head, _ := json.Marshal(map[string]string{"alg": jwt.AlgorithmES256, "kid": "test"})
+		payload, _ := json.Marshal(map[string]any{
+			"sub": "alice", "iss": "https://idp.example", "aud": "swift-codes",
+			"exp": time.Now().Add(time.Hour).Unix(), "groups": groups,
+		})
+		signed := encode(head) + "." + encode(payload)
+		digest := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		assert.NoError(t, err)
+		return signed + "." + encode(append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...))
+	}
+
+	mapped := NewJWTVerifier(validator, "groups", map[string]db.ApiKeyRole{"swift-admins": db.ApiKeyRoleAdmin, "staff": db.ApiKeyRoleReader})
+	principal, err := mapped.Verify(context.Background(), token("staff", "swift-admins", "unrelated"))
+	assert.NoError(t, err)
+	assert.Equal(t, Principal{Name: "alice", Role: db.ApiKeyRoleAdmin}, principal)
+
+	principal, err = mapped.Verify(context.Background(), token("unrelated"))
+	assert.NoError(t, err)
+	assert.Empty(t, principal.Role)
+
+	direct := NewJWTVerifier(validator, "groups", nil)
+	principal, err = direct.Verify(context.Background(), token("editor"))
+	assert.NoError(t, err)
+	assert.Equal(t, db.ApiKeyRoleEditor, principal.Role)
+
+	_, err = direct.Verify(context.Background(), token("editor")+"x")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	protected := NewAuthenticator(mapped, true).Require(Permission{Read: db.ApiKeyRoleReader, Write: db.ApiKeyRoleEditor}, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	call := func(bearer string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", nil)
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		resp := httptest.NewRecorder()
+		protected(resp, req)
+		return resp.Code
+	}
+	assert.Equal(t, http.StatusNoContent, call(token("swift-admins")))
+	assert.Equal(t, http.StatusForbidden, call(token("staff")))
+	assert.Equal(t, http.StatusUnauthorized, call("not-a-token"))
+}
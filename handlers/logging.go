@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const maxRequestIDLength = 128
+
+type requestLogContextKey struct{}
+
+// requestLog collects what handlers learn about a request for the line LogRequests writes once it completes.
+type requestLog struct {
+	swiftCode string
+	principal string
+	traceID   string
+}
+
+// logSwiftCode records the SWIFT code a request is about in its access log line.
+func logSwiftCode(r *http.Request, swiftCode string) {
+	if entry, ok := r.Context().Value(requestLogContextKey{}).(*requestLog); ok {
+		entry.swiftCode = swiftCode
+	}
+}
+
+func logTraceID(r *http.Request, traceID string) {
+	if entry, ok := r.Context().Value(requestLogContextKey{}).(*requestLog); ok {
+		entry.traceID = traceID
+	}
+}
+
+func logPrincipal(ctx context.Context, principal Principal) {
+	if entry, ok := ctx.Value(requestLogContextKey{}).(*requestLog); ok {
+		entry.principal = principal.Name
+	}
+}
+
+// requestLogger returns the default logger with the request ID of r attached, for handlers reporting
+// failures they can no longer send to the client.
+func requestLogger(r *http.Request) *slog.Logger {
+	return slog.Default().With("request_id", r.Header.Get(requestIDHeader))
+}
+
+// RequestID keeps the X-Request-ID a client sent, or assigns a new one, and echoes it in the response.
+// Error bodies and audit entries pick it up from there.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+			r.Header.Set(requestIDHeader, id)
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, which streaming exports flush.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// LogRequests writes one line per request with its method, path, status and latency. It has to run
+// inside RequestID to log the request ID.
+func LogRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &requestLog{}
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), requestLogContextKey{}, entry)))
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("request_id", r.Header.Get(requestIDHeader)),
+		}
+		if entry.swiftCode != "" {
+			attrs = append(attrs, slog.String("swift_code", entry.swiftCode))
+		}
+		if entry.principal != "" {
+			attrs = append(attrs, slog.String("principal", entry.principal))
+		}
+		if entry.traceID != "" {
+			attrs = append(attrs, slog.String("trace_id", entry.traceID))
+		}
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(r.Context(), level, "request", attrs...)
+	})
+}
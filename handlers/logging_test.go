@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDIsLoggedAndReturnedInErrors(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	handler := RequestID(LogRequests(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logSwiftCode(r, "BPKOPLPWXXX")
+		sendJSONError(w, http.StatusNotFound, "Bank not found")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/BPKOPLPWXXX", nil)
+	req.Header.Set("X-Request-ID", "client-id-1")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(t, "client-id-1", resp.Header().Get("X-Request-ID"))
+	var body ErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, ErrorResponse{Error: "Bank not found", RequestID: "client-id-1"}, body)
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &line))
+	assert.Equal(t, "GET", line["method"])
+	assert.Equal(t, "/v1/swift-codes/BPKOPLPWXXX", line["path"])
+	assert.Equal(t, float64(http.StatusNotFound), line["status"])
+	assert.Equal(t, "client-id-1", line["request_id"])
+	assert.Equal(t, "BPKOPLPWXXX", line["swift_code"])
+	assert.Contains(t, line, "latency_ms")
+
+	generated := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/BPKOPLPWXXX", nil)
+	generated.Header.Set("X-Request-ID", "has spaces")
+	generatedResp := httptest.NewRecorder()
+	handler.ServeHTTP(generatedResp, generated)
+	assert.Len(t, generatedResp.Header().Get("X-Request-ID"), 32)
+	assert.Contains(t, generatedResp.Body.String(), generatedResp.Header().Get("X-Request-ID"))
+}
+
+func TestRequestLoggerCarriesTheRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/export", nil)
+	req.Header.Set("X-Request-ID", "export-id-1")
+	requestLogger(req).Error("export failed")
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &line))
+	assert.Equal(t, "export failed", line["msg"])
+	assert.Equal(t, "export-id-1", line["request_id"])
+}
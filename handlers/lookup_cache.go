@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	cache "github.com/mateuszkochelski/SwiftCodeDb/cache"
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	metrics "github.com/mateuszkochelski/SwiftCodeDb/metrics"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const (
+	cacheBanks     = "banks"
+	cacheBranches  = "branches"
+	cacheCountries = "countries"
+)
+
+var cacheLookups = metrics.Default.NewCounterVec("cache_lookups_total", "Lookups served by the in-process cache, by cache and whether the entry was cached.", "cache", "result")
+
+// Lookups are keyed by the day they see the directory as of, which is all the queries compare.
+type bankKey struct {
+	swiftCode      string
+	asOf           string
+	includeDeleted bool
+}
+
+type branchesKey struct {
+	bicPrefix      string
+	swiftCode      string
+	asOf           string
+	includeDeleted bool
+}
+
+type countryKey struct {
+	countryCode    string
+	asOf           string
+	includeDeleted bool
+}
+
+type countryListing struct {
+	country db.Country
+	banks   []db.GetBanksByCountryCodeRow
+}
+
+// lookupCache holds the results of the lookups behind the read endpoints. A nil lookupCache caches nothing.
+type lookupCache struct {
+	banks     *cache.LRU[bankKey, db.GetBankBySwiftCodeWithCountryRow]
+	branches  *cache.LRU[branchesKey, []db.GetBanksBranchesBySwiftCodePrefixRow]
+	countries *cache.LRU[countryKey, countryListing]
+}
+
+func newLookupCache(size int, ttl time.Duration) *lookupCache {
+	return &lookupCache{
+		banks:     cache.New[bankKey, db.GetBankBySwiftCodeWithCountryRow](size, ttl),
+		branches:  cache.New[branchesKey, []db.GetBanksBranchesBySwiftCodePrefixRow](size, ttl),
+		countries: cache.New[countryKey, countryListing](size, ttl),
+	}
+}
+
+func cachedLoad[K comparable, V any](c *cache.LRU[K, V], name string, key K, load func() (V, error)) (V, error) {
+	value, hit, err := c.Load(key, load)
+	if hit {
+		cacheLookups.Inc(name, lookupHit)
+	} else {
+		cacheLookups.Inc(name, lookupMiss)
+	}
+	return value, err
+}
+
+func (c *lookupCache) bank(ctx context.Context, queries *db.Queries, params db.GetBankBySwiftCodeWithCountryParams) (db.GetBankBySwiftCodeWithCountryRow, error) {
+	load := func() (db.GetBankBySwiftCodeWithCountryRow, error) {
+		return queries.GetBankBySwiftCodeWithCountry(ctx, params)
+	}
+	if c == nil {
+		return load()
+	}
+	key := bankKey{swiftCode: params.SwiftCode, asOf: params.AsOf.Format(time.DateOnly), includeDeleted: params.IncludeDeleted}
+	return cachedLoad(c.banks, cacheBanks, key, load)
+}
+
+func (c *lookupCache) branchesOf(ctx context.Context, queries *db.Queries, params db.GetBanksBranchesBySwiftCodePrefixParams) ([]db.GetBanksBranchesBySwiftCodePrefixRow, error) {
+	load := func() ([]db.GetBanksBranchesBySwiftCodePrefixRow, error) {
+		return queries.GetBanksBranchesBySwiftCodePrefix(ctx, params)
+	}
+	if c == nil {
+		return load()
+	}
+	key := branchesKey{bicPrefix: params.BicPrefix, swiftCode: params.SwiftCode, asOf: params.AsOf.Format(time.DateOnly), includeDeleted: params.IncludeDeleted}
+	return cachedLoad(c.branches, cacheBranches, key, load)
+}
+
+func (c *lookupCache) country(ctx context.Context, queries *db.Queries, params db.GetBanksByCountryCodeParams) (countryListing, error) {
+	load := func() (countryListing, error) {
+		country, err := queries.GetCountry(ctx, params.CountryCode)
+		if err != nil {
+			return countryListing{}, err
+		}
+		banks, err := queries.GetBanksByCountryCode(ctx, params)
+		if err != nil {
+			return countryListing{}, err
+		}
+		return countryListing{country: country, banks: banks}, nil
+	}
+	if c == nil {
+		return load()
+	}
+	key := countryKey{countryCode: params.CountryCode, asOf: params.AsOf.Format(time.DateOnly), includeDeleted: params.IncludeDeleted}
+	return cachedLoad(c.countries, cacheCountries, key, load)
+}
+
+// invalidate drops every lookup a change may have made stale: those of the code itself, the branch
+// listings of its institution, and the country listings it is in now or was in before.
+func (c *lookupCache) invalidate(changes ...store.Change) {
+	if c == nil {
+		return
+	}
+	for _, change := range changes {
+		if change.All {
+			c.purge()
+			continue
+		}
+		prefix := models.InstitutionPrefix(change.SwiftCode)
+		c.banks.RemoveFunc(func(key bankKey, _ db.GetBankBySwiftCodeWithCountryRow) bool {
+			return key.swiftCode == change.SwiftCode
+		})
+		c.branches.RemoveFunc(func(key branchesKey, _ []db.GetBanksBranchesBySwiftCodePrefixRow) bool {
+			return key.bicPrefix == prefix
+		})
+		c.countries.RemoveFunc(func(key countryKey, listing countryListing) bool {
+			return key.countryCode == change.CountryCode || slices.ContainsFunc(listing.banks, func(bank db.GetBanksByCountryCodeRow) bool {
+				return bank.SwiftCode == change.SwiftCode
+			})
+		})
+	}
+}
+
+// deletedChanges describes the removal of swiftCodes; the country listings they were in are found by their contents.
+func deletedChanges(swiftCodes []string) []store.Change {
+	changes := make([]store.Change, len(swiftCodes))
+	for i, swiftCode := range swiftCodes {
+		changes[i] = store.Change{SwiftCode: swiftCode}
+	}
+	return changes
+}
+
+func (c *lookupCache) purge() {
+	c.banks.Purge()
+	c.branches.Purge()
+	c.countries.Purge()
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	metrics "github.com/mateuszkochelski/SwiftCodeDb/metrics"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCacheInvalidatesLookupsAffectedByChange(t *testing.T) {
+	c := newLookupCache(10, time.Minute)
+	c.banks.Add(bankKey{swiftCode: "AAAAPLPWXXX", asOf: "2025-01-01"}, db.GetBankBySwiftCodeWithCountryRow{})
+	c.banks.Add(bankKey{swiftCode: "BBBBPLPWXXX", asOf: "2025-01-01"}, db.GetBankBySwiftCodeWithCountryRow{})
+	c.branches.Add(branchesKey{bicPrefix: "AAAAPLPW", swiftCode: "AAAAPLPWXXX"}, nil)
+	c.branches.Add(branchesKey{bicPrefix: "BBBBPLPW", swiftCode: "BBBBPLPWXXX"}, nil)
+	c.countries.Add(countryKey{countryCode: "PL"}, countryListing{})
+	c.countries.Add(countryKey{countryCode: "DE"}, countryListing{banks: []db.GetBanksByCountryCodeRow{{SwiftCode: "AAAAPLPWKRK"}}})
+	c.countries.Add(countryKey{countryCode: "FR"}, countryListing{})
+
+	c.invalidate(store.Change{SwiftCode: "AAAAPLPWKRK", CountryCode: "PL"})
+	assert.Equal(t, 2, c.banks.Len(), "only lookups of the changed code are dropped")
+	_, ok := c.branches.Get(branchesKey{bicPrefix: "AAAAPLPW", swiftCode: "AAAAPLPWXXX"})
+	assert.False(t, ok, "branch listings of the institution are dropped")
+	assert.Equal(t, 1, c.branches.Len())
+	_, ok = c.countries.Get(countryKey{countryCode: "FR"})
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.countries.Len(), "the new country and the one listing the code are dropped")
+
+	c.invalidate(store.Change{SwiftCode: "AAAAPLPWXXX"})
+	_, ok = c.banks.Get(bankKey{swiftCode: "AAAAPLPWXXX", asOf: "2025-01-01"})
+	assert.False(t, ok)
+
+	c.invalidate(store.Change{All: true})
+	assert.Equal(t, 0, c.banks.Len()+c.branches.Len()+c.countries.Len())
+
+	var disabled *lookupCache
+	disabled.invalidate(store.Change{SwiftCode: "AAAAPLPWXXX"})
+}
+
+func TestCachedLookupsAreInvalidatedByWrites(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	handler.EnableCache(100, time.Minute)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Cached Bank",
+		SwiftCode:   "CACHPLPWXXX",
+		CountryCode: "PL",
+		BankType:    models.BankType(true),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "CACHPLPWXXX")
+
+	for i := 0; i < 2; i++ {
+		getResp := httptest.NewRecorder()
+		handler.HandleSwiftCodes(getResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/CACHPLPWXXX", nil))
+		assert.Equal(t, http.StatusOK, getResp.Code)
+	}
+	countryResp := httptest.NewRecorder()
+	handler.GetBanksByContryCode(countryResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/country/PL", nil))
+	assert.Contains(t, countryResp.Body.String(), "CACHPLPWXXX")
+
+	var out bytes.Buffer
+	assert.NoError(t, metrics.Default.WriteText(&out))
+	assert.Contains(t, out.String(), `cache_lookups_total{cache="banks",result="hit"}`)
+
+	deleteResp := httptest.NewRecorder()
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/CACHPLPWXXX", nil)
+	deleteReq.Header.Set("If-Match", "*")
+	handler.HandleSwiftCodes(deleteResp, deleteReq)
+	assert.Equal(t, http.StatusCreated, deleteResp.Code)
+
+	getResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(getResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/CACHPLPWXXX", nil))
+	assert.Equal(t, http.StatusNotFound, getResp.Code)
+	countryResp = httptest.NewRecorder()
+	handler.GetBanksByContryCode(countryResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/country/PL", nil))
+	assert.NotContains(t, countryResp.Body.String(), "CACHPLPWXXX")
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	metrics "github.com/mateuszkochelski/SwiftCodeDb/metrics"
+)
+
+const (
+	lookupHit  = "hit"
+	lookupMiss = "miss"
+)
+
+var (
+	httpRequests        = metrics.Default.NewCounterVec("http_requests_total", "HTTP requests served, by route, method and status.", "route", "method", "status")
+	httpRequestDuration = metrics.Default.NewHistogramVec("http_request_duration_seconds", "Time taken to serve HTTP requests.", metrics.DefaultBuckets, "route", "method")
+	swiftCodeLookups    = metrics.Default.NewCounterVec("swift_code_lookups_total", "Lookups of a single SWIFT code, by whether a bank was found.", "result")
+)
+
+// Instrument counts the requests served on route and observes how long they take. Routes are the
+// registered patterns rather than request paths, which keeps the number of series bounded.
+func Instrument(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		method := metricMethod(r.Method)
+		httpRequests.Inc(route, method, strconv.Itoa(recorder.status))
+		httpRequestDuration.Observe(time.Since(start).Seconds(), route, method)
+	})
+}
+
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "OTHER"
+}
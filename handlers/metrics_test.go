@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metrics "github.com/mateuszkochelski/SwiftCodeDb/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentRecordsRequestsPerRoute(t *testing.T) {
+	handler := Instrument("/v1/test-instrument/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sendJSONError(w, http.StatusTeapot, "Teapot")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/test-instrument/A", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/v1/test-instrument/B", nil))
+
+	var out bytes.Buffer
+	assert.NoError(t, metrics.Default.WriteText(&out))
+	assert.Contains(t, out.String(), `http_requests_total{route="/v1/test-instrument/",method="GET",status="418"} 1`)
+	assert.Contains(t, out.String(), `http_requests_total{route="/v1/test-instrument/",method="OTHER",status="418"} 1`)
+	assert.Contains(t, out.String(), `http_request_duration_seconds_count{route="/v1/test-instrument/",method="GET"} 1`)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ratelimit "github.com/mateuszkochelski/SwiftCodeDb/ratelimit"
+)
+
+// RateLimiter throttles each client with a token bucket, one for reads (GET and HEAD) and one for
+// every other method. Clients are told apart by their principal, or by IP address when anonymous.
+// Requests carrying credentials are also throttled by IP address before the credentials are checked.
+type RateLimiter struct {
+	store          ratelimit.Store
+	reads          ratelimit.Limit
+	writes         ratelimit.Limit
+	credentials    ratelimit.Limit
+	trustedProxies int
+}
+
+// NewRateLimiter returns a RateLimiter. Behind trustedProxies reverse proxies that append to X-Forwarded-For,
+// the client address is the entry the outermost of them added; with none the header is ignored.
+func NewRateLimiter(store ratelimit.Store, reads ratelimit.Limit, writes ratelimit.Limit, credentials ratelimit.Limit, trustedProxies int) *RateLimiter {
+	return &RateLimiter{store: store, reads: reads, writes: writes, credentials: credentials, trustedProxies: trustedProxies}
+}
+
+// Limit serves next only while the client has tokens left. It has to run after authentication to
+// see the principal. Should the store fail, requests are let through rather than rejected.
+func (l *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		class, limit := "write", l.writes
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			class, limit = "read", l.reads
+		}
+		l.take(w, r, class, l.clientKey(r), limit, next)
+	}
+}
+
+// LimitCredentials serves next, which authenticates, only while the client address has tokens left for
+// requests carrying credentials. Running before authentication, it keeps floods of bad API keys or tokens
+// from reaching the key lookups and key set refreshes behind them.
+func (l *RateLimiter) LimitCredentials(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next(w, r)
+			return
+		}
+		l.take(w, r, "credentials", l.addressKey(r), l.credentials, next)
+	}
+}
+
+func (l *RateLimiter) take(w http.ResponseWriter, r *http.Request, class string, key string, limit ratelimit.Limit, next http.HandlerFunc) {
+	if limit.Unlimited() {
+		next(w, r)
+		return
+	}
+
+	decision, err := l.store.Take(r.Context(), class+":"+key, limit)
+	if err != nil {
+		requestLogger(r).WarnContext(r.Context(), "rate limit store failed, letting request through", "error", err)
+		next(w, r)
+		return
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	if !decision.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+		sendProblem(w, http.StatusTooManyRequests, "Rate limit of %d %s requests exceeded", decision.Limit, class)
+		return
+	}
+	next(w, r)
+}
+
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal.Name
+	}
+	return l.addressKey(r)
+}
+
+func (l *RateLimiter) addressKey(r *http.Request) string {
+	if client := l.forwardedFor(r); client != "" {
+		return "ip:" + client
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// forwardedFor returns the client address recorded by the outermost trusted proxy. Entries to its left come
+// from the client and are ignored, as anyone can send them. With fewer entries than trusted proxies, the
+// leftmost entry is the best there is.
+func (l *RateLimiter) forwardedFor(r *http.Request) string {
+	if l.trustedProxies <= 0 {
+		return ""
+	}
+	var entries []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, entry := range strings.Split(header, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[max(len(entries)-l.trustedProxies, 0)]
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ratelimit "github.com/mateuszkochelski/SwiftCodeDb/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterRejectsClientsOverTheirBudget(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 1, Burst: 2}, ratelimit.Limit{Rate: 1, Burst: 1}, ratelimit.Limit{}, 0)
+	limited := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	call := func(method string, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/swift-codes/country/PL", nil)
+		req.RemoteAddr = remoteAddr
+		resp := httptest.NewRecorder()
+		limited(resp, req)
+		return resp
+	}
+
+	first := call(http.MethodGet, "10.0.0.1:1234")
+	assert.Equal(t, http.StatusNoContent, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusNoContent, call(http.MethodGet, "10.0.0.1:5678").Code)
+
+	rejected := call(http.MethodGet, "10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "1", rejected.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rejected.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "application/problem+json", rejected.Header().Get("Content-Type"))
+
+	assert.Equal(t, http.StatusNoContent, call(http.MethodGet, "10.0.0.2:1234").Code, "clients have separate buckets")
+	assert.Equal(t, http.StatusNoContent, call(http.MethodPost, "10.0.0.1:1234").Code, "writes have their own bucket")
+	assert.Equal(t, http.StatusTooManyRequests, call(http.MethodDelete, "10.0.0.1:1234").Code)
+
+	unlimited := NewRateLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, ratelimit.Limit{}, ratelimit.Limit{}, 0).Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	resp := httptest.NewRecorder()
+	unlimited(resp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/country/PL", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimiterThrottlesCredentialsBeforeTheyAreChecked(t *testing.T) {
+	checked := 0
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, ratelimit.Limit{}, ratelimit.Limit{Rate: 1, Burst: 2}, 0)
+	limited := limiter.LimitCredentials(func(w http.ResponseWriter, r *http.Request) {
+		checked++
+		sendProblem(w, http.StatusUnauthorized, "invalid credentials")
+	})
+	call := func(authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/country/PL", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp := httptest.NewRecorder()
+		limited(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call("Bearer bad-1"))
+	assert.Equal(t, http.StatusUnauthorized, call("Bearer bad-2"))
+	assert.Equal(t, http.StatusTooManyRequests, call("Bearer bad-3"), "new credentials do not get new buckets")
+	assert.Equal(t, 2, checked)
+	assert.Equal(t, http.StatusUnauthorized, call(""), "requests without credentials are left to the other limits")
+}
+
+func TestRateLimiterKeysAnonymousClientsByTrustedForwardedFor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/country/PL", nil)
+	req.RemoteAddr = "10.0.0.9:1234"
+	req.Header.Add("X-Forwarded-For", "6.6.6.6, 203.0.113.7")
+	req.Header.Add("X-Forwarded-For", "198.51.100.2")
+
+	tests := []struct {
+		trustedProxies int
+		key            string
+	}{
+		{trustedProxies: 0, key: "ip:10.0.0.9"},
+		{trustedProxies: 1, key: "ip:198.51.100.2"},
+		{trustedProxies: 2, key: "ip:203.0.113.7"},
+		{trustedProxies: 5, key: "ip:6.6.6.6"},
+	}
+	for _, tc := range tests {
+		limiter := NewRateLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, ratelimit.Limit{}, ratelimit.Limit{}, tc.trustedProxies)
+		assert.Equal(t, tc.key, limiter.clientKey(req), "behind %d proxies", tc.trustedProxies)
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+type RedirectRequest struct {
+	OldSwiftCode string `json:"oldSwiftCode"`
+	NewSwiftCode string `json:"newSwiftCode"`
+}
+
+type RedirectResponse struct {
+	OldSwiftCode string    `json:"oldSwiftCode"`
+	NewSwiftCode string    `json:"newSwiftCode"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (h *BankHandler) HandleRedirects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ListRedirects(w, r)
+	case http.MethodPost:
+		h.CreateRedirect(w, r)
+	default:
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *BankHandler) CreateRedirect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	var request RedirectRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	request.OldSwiftCode = strings.ToUpper(strings.TrimSpace(request.OldSwiftCode))
+	request.NewSwiftCode = strings.ToUpper(strings.TrimSpace(request.NewSwiftCode))
+	if !models.IsValidSwiftCode(request.OldSwiftCode) || !models.IsValidSwiftCode(request.NewSwiftCode) {
+		sendJSONError(w, http.StatusBadRequest, "oldSwiftCode and newSwiftCode must be valid 11 character swift codes")
+		return
+	}
+	if request.OldSwiftCode == request.NewSwiftCode {
+		sendJSONError(w, http.StatusBadRequest, "A swift code cannot redirect to itself")
+		return
+	}
+
+	var redirect db.BicRedirect
+	var apiErr *apiError
+	err = store.ExecTx(r.Context(), h.conn, func(queries *db.Queries) error {
+		redirect, apiErr = createRedirect(r.Context(), queries, request)
+		if apiErr != nil {
+			return errRollback
+		}
+		return nil
+	})
+	if apiErr != nil {
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(RedirectResponse(redirect))
+}
+
+// createRedirect registers a retired code, refusing codes still in use and mappings that would close a cycle.
+func createRedirect(ctx context.Context, queries *db.Queries, request RedirectRequest) (db.BicRedirect, *apiError) {
+	_, err := queries.GetBankBySwiftCodeWithCountry(ctx, db.GetBankBySwiftCodeWithCountryParams{SwiftCode: request.OldSwiftCode, AsOf: time.Now()})
+	if err == nil {
+		return db.BicRedirect{}, newAPIError(http.StatusConflict, "Bank %s is still active", request.OldSwiftCode)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return db.BicRedirect{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	_, err = queries.GetBicRedirect(ctx, request.OldSwiftCode)
+	if err == nil {
+		return db.BicRedirect{}, newAPIError(http.StatusConflict, "%s is already redirected", request.OldSwiftCode)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return db.BicRedirect{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	successor, err := store.ResolveRedirect(ctx, queries, request.NewSwiftCode)
+	if errors.Is(err, store.ErrRedirectCycle) {
+		return db.BicRedirect{}, newAPIError(http.StatusConflict, "%s", err.Error())
+	}
+	if err != nil {
+		return db.BicRedirect{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	if successor == request.OldSwiftCode {
+		return db.BicRedirect{}, newAPIError(http.StatusConflict, "Redirecting %s to %s would create a cycle", request.OldSwiftCode, request.NewSwiftCode)
+	}
+
+	_, err = queries.GetBankBySwiftCodeWithCountry(ctx, db.GetBankBySwiftCodeWithCountryParams{SwiftCode: successor, AsOf: time.Now()})
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.BicRedirect{}, newAPIError(http.StatusUnprocessableEntity, "Successor bank %s not found", successor)
+	}
+	if err != nil {
+		return db.BicRedirect{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	redirect, err := queries.CreateBicRedirect(ctx, db.CreateBicRedirectParams{
+		OldSwiftCode: request.OldSwiftCode,
+		NewSwiftCode: request.NewSwiftCode,
+	})
+	if err != nil {
+		return db.BicRedirect{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	return redirect, nil
+}
+
+func (h *BankHandler) ListRedirects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	rows, err := h.queries.ListBicRedirects(r.Context())
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := []RedirectResponse{}
+	for _, row := range rows {
+		response = append(response, RedirectResponse(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *BankHandler) DeleteRedirect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	oldSwiftCode := strings.TrimPrefix(r.URL.Path, "/v1/redirects/")
+	logSwiftCode(r, oldSwiftCode)
+	_, err := h.queries.DeleteBicRedirect(r.Context(), oldSwiftCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		sendJSONError(w, http.StatusNotFound, "Redirect not found")
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Redirect deleted successfully"})
+}
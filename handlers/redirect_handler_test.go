@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetiredSwiftCodeRedirectsToSuccessor(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Merged Bank",
+		SwiftCode:   "MERGPLPWKRK",
+		CountryCode: "PL",
+		BankType:    models.BankType(false),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "MERGPLPWKRK")
+
+	register := func(oldCode, newCode string) int {
+		resp := httptest.NewRecorder()
+		body := `{"oldSwiftCode": "` + oldCode + `", "newSwiftCode": "` + newCode + `"}`
+		handler.HandleRedirects(resp, httptest.NewRequest(http.MethodPost, "/v1/redirects", strings.NewReader(body)))
+		return resp.Code
+	}
+	assert.Equal(t, http.StatusCreated, register("RETIPLPWKRK", "MERGPLPWKRK"))
+	defer queries.DeleteBicRedirect(context.Background(), "RETIPLPWKRK")
+	assert.Equal(t, http.StatusCreated, register("OLDEPLPWKRK", "RETIPLPWKRK"))
+	defer queries.DeleteBicRedirect(context.Background(), "OLDEPLPWKRK")
+	assert.Equal(t, http.StatusConflict, register("MERGPLPWKRK", "OLDEPLPWKRK"))
+
+	getResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(getResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/OLDEPLPWKRK", nil))
+	assert.Equal(t, http.StatusOK, getResp.Code)
+
+	var bank BankResponse
+	err = json.NewDecoder(getResp.Body).Decode(&bank)
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGPLPWKRK", bank.SwiftCode)
+	assert.Equal(t, "OLDEPLPWKRK", bank.RedirectedFrom)
+
+	noFollowResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(noFollowResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/OLDEPLPWKRK?follow=false", nil))
+	assert.Equal(t, http.StatusMovedPermanently, noFollowResp.Code)
+	assert.Equal(t, "/v1/swift-codes/MERGPLPWKRK", noFollowResp.Header().Get("Location"))
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+// Snapshot names appear in URLs, so they are limited to characters that need no escaping.
+var snapshotNameFormat = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type CreateSnapshotRequest struct {
+	Name string `json:"name"`
+}
+
+type SnapshotResponse struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Banks     int64     `json:"banks"`
+}
+
+type ModifiedBank struct {
+	SwiftCode string               `json:"swiftCode"`
+	Changes   []models.FieldChange `json:"changes"`
+}
+
+type SnapshotDiffResponse struct {
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Added    []models.Bank  `json:"added"`
+	Removed  []models.Bank  `json:"removed"`
+	Modified []ModifiedBank `json:"modified"`
+}
+
+func (h *BankHandler) HandleSnapshots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ListSnapshots(w, r)
+	case http.MethodPost:
+		h.CreateSnapshot(w, r)
+	default:
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *BankHandler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	var request CreateSnapshotRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if !snapshotNameFormat.MatchString(request.Name) {
+		sendJSONError(w, http.StatusBadRequest, "Snapshot name must be 1 to 64 letters, digits, '.', '_' or '-'")
+		return
+	}
+
+	var response SnapshotResponse
+	var apiErr *apiError
+	err = store.ExecTx(r.Context(), h.conn, func(queries *db.Queries) error {
+		response, apiErr = createSnapshot(r.Context(), queries, request.Name)
+		if apiErr != nil {
+			return errRollback
+		}
+		return nil
+	})
+	if apiErr != nil {
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func createSnapshot(ctx context.Context, queries *db.Queries, name string) (SnapshotResponse, *apiError) {
+	_, err := queries.GetSnapshotByName(ctx, name)
+	if err == nil {
+		return SnapshotResponse{}, newAPIError(http.StatusConflict, "Snapshot %s already exists", name)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return SnapshotResponse{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	snapshot, err := queries.CreateSnapshot(ctx, name)
+	if err != nil {
+		return SnapshotResponse{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	banks, err := queries.CopyBanksToSnapshot(ctx, snapshot.ID)
+	if err != nil {
+		return SnapshotResponse{}, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	return SnapshotResponse{Name: snapshot.Name, CreatedAt: snapshot.CreatedAt, Banks: banks}, nil
+}
+
+func (h *BankHandler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	rows, err := h.queries.ListSnapshots(r.Context())
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := []SnapshotResponse{}
+	for _, row := range rows {
+		response = append(response, SnapshotResponse{Name: row.Name, CreatedAt: row.CreatedAt, Banks: row.BankCount})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DiffSnapshots serves /v1/snapshots/{from}/diff/{to}.
+func (h *BankHandler) DiffSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	from, to, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/snapshots/"), "/diff/")
+	if !ok || from == "" || to == "" {
+		sendJSONError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	fromSnapshot, err := h.queries.GetSnapshotByName(r.Context(), from)
+	if err != nil {
+		sendSnapshotLookupError(w, err, from)
+		return
+	}
+	toSnapshot, err := h.queries.GetSnapshotByName(r.Context(), to)
+	if err != nil {
+		sendSnapshotLookupError(w, err, to)
+		return
+	}
+
+	rows, err := h.queries.DiffSnapshots(r.Context(), db.DiffSnapshotsParams{
+		FromSnapshotID: fromSnapshot.ID,
+		ToSnapshotID:   toSnapshot.ID,
+	})
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := SnapshotDiffResponse{
+		From:     from,
+		To:       to,
+		Added:    []models.Bank{},
+		Removed:  []models.Bank{},
+		Modified: []ModifiedBank{},
+	}
+	for _, row := range rows {
+		before := models.ConvertToBank(db.SnapshotBank{
+			SwiftCode:   row.OldSwiftCode.String,
+			BankName:    row.OldBankName.String,
+			BankAddress: row.OldBankAddress,
+			CountryCode: row.OldCountryCode.String,
+			BankType:    row.OldBankType.BankType,
+			ValidFrom:   row.OldValidFrom,
+			ValidTo:     row.OldValidTo,
+		})
+		after := models.ConvertToBank(db.SnapshotBank{
+			SwiftCode:   row.NewSwiftCode.String,
+			BankName:    row.NewBankName.String,
+			BankAddress: row.NewBankAddress,
+			CountryCode: row.NewCountryCode.String,
+			BankType:    row.NewBankType.BankType,
+			ValidFrom:   row.NewValidFrom,
+			ValidTo:     row.NewValidTo,
+		})
+
+		switch {
+		case !row.OldSwiftCode.Valid:
+			response.Added = append(response.Added, after)
+		case !row.NewSwiftCode.Valid:
+			response.Removed = append(response.Removed, before)
+		default:
+			response.Modified = append(response.Modified, ModifiedBank{SwiftCode: after.SwiftCode, Changes: models.CompareBanks(before, after)})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func sendSnapshotLookupError(w http.ResponseWriter, err error, name string) {
+	if errors.Is(err, sql.ErrNoRows) {
+		sendJSONError(w, http.StatusNotFound, "Snapshot %s not found", name)
+		return
+	}
+	sendJSONError(w, http.StatusInternalServerError, "Internal server error")
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotDiffReportsAddedRemovedAndModifiedBanks(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	for _, code := range []string{"SNAPPLPWKRK", "SNAPPLPWGDN"} {
+		err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+			BankName:    "Snapshot Bank",
+			SwiftCode:   code,
+			CountryCode: "PL",
+			BankType:    models.BankType(false),
+		}, store.AuditMetadata{})
+		assert.NoError(t, err)
+		defer queries.DeleteBankBySwiftCode(context.Background(), code)
+	}
+
+	createSnapshot := func(name string) {
+		resp := httptest.NewRecorder()
+		handler.HandleSnapshots(resp, httptest.NewRequest(http.MethodPost, "/v1/snapshots", strings.NewReader(`{"name": "`+name+`"}`)))
+		assert.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	}
+	createSnapshot("march-" + suffix)
+
+	_, err = queries.DeleteBankBySwiftCode(context.Background(), "SNAPPLPWGDN")
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Snapshot Bank",
+		SwiftCode:   "SNAPPLPWWAW",
+		CountryCode: "PL",
+		BankType:    models.BankType(false),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "SNAPPLPWWAW")
+	_, err = dbConn.Exec("UPDATE banks SET bank_address = 'NEW STREET' WHERE swift_code = 'SNAPPLPWKRK'")
+	assert.NoError(t, err)
+	createSnapshot("april-" + suffix)
+
+	duplicateResp := httptest.NewRecorder()
+	handler.HandleSnapshots(duplicateResp, httptest.NewRequest(http.MethodPost, "/v1/snapshots", strings.NewReader(`{"name": "april-`+suffix+`"}`)))
+	assert.Equal(t, http.StatusConflict, duplicateResp.Code)
+
+	diffResp := httptest.NewRecorder()
+	handler.DiffSnapshots(diffResp, httptest.NewRequest(http.MethodGet, "/v1/snapshots/march-"+suffix+"/diff/april-"+suffix, nil))
+	assert.Equal(t, http.StatusOK, diffResp.Code)
+
+	var diff SnapshotDiffResponse
+	err = json.NewDecoder(diffResp.Body).Decode(&diff)
+	assert.NoError(t, err)
+	assert.Contains(t, diff.Added, models.Bank{BankName: "Snapshot Bank", CountryCode: "PL", SwiftCode: "SNAPPLPWWAW"})
+	assert.Contains(t, diff.Removed, models.Bank{BankName: "Snapshot Bank", CountryCode: "PL", SwiftCode: "SNAPPLPWGDN"})
+	assert.Contains(t, diff.Modified, ModifiedBank{
+		SwiftCode: "SNAPPLPWKRK",
+		Changes:   []models.FieldChange{{Field: "address", Old: "", New: "NEW STREET"}},
+	})
+
+	missingResp := httptest.NewRecorder()
+	handler.DiffSnapshots(missingResp, httptest.NewRequest(http.MethodGet, "/v1/snapshots/march-"+suffix+"/diff/missing-"+suffix, nil))
+	assert.Equal(t, http.StatusNotFound, missingResp.Code)
+}
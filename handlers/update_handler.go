@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+)
+
+const (
+	ifMatchHeader     = "If-Match"
+	ifNoneMatchHeader = "If-None-Match"
+)
+
+// BankPatch holds the fields a PATCH may change, omitted fields keep their current value.
+type BankPatch struct {
+	Address     *string      `json:"address"`
+	BankName    *string      `json:"bankName"`
+	CountryCode *string      `json:"countryISO2"`
+	CountryName *string      `json:"countryName"`
+	ValidFrom   *models.Date `json:"validFrom"`
+	ValidTo     *models.Date `json:"validTo"`
+}
+
+// encodeRepresentation encodes a response body and derives its strong ETag from the bytes, so that a change
+// to anything the body embeds, such as a branch of a headquarter, changes the tag.
+func encodeRepresentation(response any) ([]byte, string, error) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(response); err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(body.Bytes())
+	return body.Bytes(), `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// bankETag is the ETag GetBanksBySwiftCode serves for bank today, which write preconditions are checked against.
+func bankETag(ctx context.Context, queries *db.Queries, bank db.GetBankBySwiftCodeWithCountryRow) (string, *apiError) {
+	response, apiErr := bankResponse(ctx, nil, queries, bank, time.Now(), false)
+	if apiErr != nil {
+		return "", apiErr
+	}
+	_, etag, err := encodeRepresentation(response)
+	if err != nil {
+		return "", newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	return etag, nil
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header lists etag or "*". If-None-Match uses the weak
+// comparison, where weak tags match by their opaque part; If-Match uses the strong one, where they never match.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// liveBank loads the row of swiftCode valid today, which is the one writes apply to.
+func liveBank(ctx context.Context, queries *db.Queries, swiftCode string) (db.GetBankBySwiftCodeWithCountryRow, *apiError) {
+	bank, err := queries.GetBankBySwiftCodeWithCountry(ctx, db.GetBankBySwiftCodeWithCountryParams{SwiftCode: swiftCode, AsOf: time.Now()})
+	if errors.Is(err, sql.ErrNoRows) {
+		return bank, newAPIError(http.StatusNotFound, "Bank not found")
+	}
+	if err != nil {
+		return bank, newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	return bank, nil
+}
+
+// currentBank loads the live bank a write applies to and enforces the If-Match precondition against it. The write
+// must still check the returned version, as UpdateBank and DeleteBank do, to catch changes made since.
+func currentBank(ctx context.Context, queries *db.Queries, swiftCode string, ifMatch string) (db.GetBankBySwiftCodeWithCountryRow, *apiError) {
+	bank, apiErr := liveBank(ctx, queries, swiftCode)
+	if apiErr != nil {
+		return bank, apiErr
+	}
+
+	if ifMatch == "" {
+		return bank, newAPIError(http.StatusPreconditionRequired, "%s header is required", ifMatchHeader)
+	}
+	if strings.TrimSpace(ifMatch) == "*" {
+		return bank, nil
+	}
+	etag, apiErr := bankETag(ctx, queries, bank)
+	if apiErr != nil {
+		return bank, apiErr
+	}
+	if !etagMatches(ifMatch, etag, false) {
+		return bank, newAPIError(http.StatusPreconditionFailed, "Bank was modified, current ETag is %s", etag)
+	}
+	return bank, nil
+}
+
+func (h *BankHandler) UpdateBank(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	var request models.Bank
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	h.writeBankUpdate(w, r, func(current models.Bank) (models.Bank, *apiError) {
+		if request.SwiftCode != "" && request.SwiftCode != current.SwiftCode {
+			return models.Bank{}, newAPIError(http.StatusUnprocessableEntity, "Swift code cannot be changed")
+		}
+		if request.IsHeadquarter != current.IsHeadquarter {
+			return models.Bank{}, newAPIError(http.StatusUnprocessableEntity, "isHeadquarter is determined by the swift code")
+		}
+		request.SwiftCode = current.SwiftCode
+		return request, nil
+	})
+}
+
+func (h *BankHandler) PatchBank(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	var patch BankPatch
+	err := json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	h.writeBankUpdate(w, r, func(current models.Bank) (models.Bank, *apiError) {
+		if patch.Address != nil {
+			current.Address = *patch.Address
+		}
+		if patch.BankName != nil {
+			current.BankName = *patch.BankName
+		}
+		if patch.CountryCode != nil && *patch.CountryCode != current.CountryCode {
+			if patch.CountryName == nil {
+				return models.Bank{}, newAPIError(http.StatusUnprocessableEntity, "countryName is required when countryISO2 changes")
+			}
+			current.CountryCode = *patch.CountryCode
+		}
+		if patch.CountryName != nil {
+			current.CountryName = *patch.CountryName
+		}
+		if patch.ValidFrom != nil {
+			current.ValidFrom = patch.ValidFrom
+		}
+		if patch.ValidTo != nil {
+			current.ValidTo = patch.ValidTo
+		}
+		return current, nil
+	})
+}
+
+// writeBankUpdate runs a PUT or PATCH, where apply turns the current bank into its new version.
+func (h *BankHandler) writeBankUpdate(w http.ResponseWriter, r *http.Request, apply func(models.Bank) (models.Bank, *apiError)) {
+	swiftCode := strings.TrimPrefix(r.URL.Path, "/v1/swift-codes/")
+	logSwiftCode(r, swiftCode)
+
+	var updated models.Bank
+	var etag string
+	var apiErr *apiError
+	err := store.ExecTx(r.Context(), h.conn, func(queries *db.Queries) error {
+		updated, etag, apiErr = updateBank(r.Context(), queries, swiftCode, r.Header.Get(ifMatchHeader), apply, auditMetadataFromRequest(r))
+		if apiErr != nil {
+			return errRollback
+		}
+		return nil
+	})
+	if apiErr != nil {
+		sendJSONError(w, apiErr.status, "%s", apiErr.message)
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	h.cache.invalidate(store.Change{SwiftCode: swiftCode, CountryCode: updated.CountryCode})
+
+	w.Header().Set("Content-Type", "application/json")
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+func updateBank(ctx context.Context, queries *db.Queries, swiftCode string, ifMatch string, apply func(models.Bank) (models.Bank, *apiError), meta store.AuditMetadata) (models.Bank, string, *apiError) {
+	current, apiErr := currentBank(ctx, queries, swiftCode, ifMatch)
+	if apiErr != nil {
+		return models.Bank{}, "", apiErr
+	}
+
+	before := models.ConvertToBank(current)
+	after, apiErr := apply(before)
+	if apiErr != nil {
+		return models.Bank{}, "", apiErr
+	}
+	if after.BankName == "" {
+		return models.Bank{}, "", newAPIError(http.StatusUnprocessableEntity, "bankName must not be empty")
+	}
+	if after.ValidFrom != nil && after.ValidTo != nil && !after.ValidTo.After(after.ValidFrom.Time) {
+		return models.Bank{}, "", newAPIError(http.StatusUnprocessableEntity, "validTo must be after validFrom")
+	}
+
+	// An undated start reaches back indefinitely, so it overlaps every earlier window of the code.
+	var startsOn time.Time
+	if after.ValidFrom != nil {
+		startsOn = after.ValidFrom.Time
+	}
+	overlapping, err := queries.CountOverlappingBanks(ctx, db.CountOverlappingBanksParams{
+		SwiftCode: swiftCode,
+		ExcludeID: current.ID,
+		ValidFrom: startsOn,
+		ValidTo:   after.ValidTo.NullTime(),
+	})
+	if err != nil {
+		return models.Bank{}, "", newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	if overlapping > 0 {
+		return models.Bank{}, "", newAPIError(http.StatusConflict, "Validity overlaps another validity window of %s", swiftCode)
+	}
+
+	err = store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: after.CountryCode, CountryName: after.CountryName})
+	if err != nil {
+		return models.Bank{}, "", newAPIError(http.StatusUnprocessableEntity, "Error during country insertion: %s", err.Error())
+	}
+
+	row, err := queries.UpdateBank(ctx, db.UpdateBankParams{
+		BankName:    after.BankName,
+		BankAddress: sql.NullString{String: after.Address, Valid: len(after.Address) != 0},
+		CountryCode: after.CountryCode,
+		ValidFrom:   after.ValidFrom.NullTime(),
+		ValidTo:     after.ValidTo.NullTime(),
+		ID:          current.ID,
+		Version:     current.Version,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bank{}, "", newAPIError(http.StatusPreconditionFailed, "Bank was modified concurrently")
+	}
+	if err != nil {
+		return models.Bank{}, "", newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	updated := models.ConvertToBank(row)
+	updated.CountryName = after.CountryName
+	if err := store.RecordAudit(ctx, queries, meta, db.AuditOperationUpdate, swiftCode, &before, &updated); err != nil {
+		return models.Bank{}, "", newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	if err := store.NotifyChanges(ctx, queries, store.Change{SwiftCode: swiftCode, CountryCode: updated.CountryCode}); err != nil {
+		return models.Bank{}, "", newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	// A bank moved out of today's validity has no current representation, and so no ETag.
+	live, err := queries.GetBankBySwiftCodeWithCountry(ctx, db.GetBankBySwiftCodeWithCountryParams{SwiftCode: swiftCode, AsOf: time.Now()})
+	if errors.Is(err, sql.ErrNoRows) {
+		return updated, "", nil
+	}
+	if err != nil {
+		return models.Bank{}, "", newAPIError(http.StatusInternalServerError, "Internal Server Error")
+	}
+	etag, apiErr := bankETag(ctx, queries, live)
+	if apiErr != nil {
+		return models.Bank{}, "", apiErr
+	}
+	return updated, etag, nil
+}
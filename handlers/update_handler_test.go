@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	store "github.com/mateuszkochelski/SwiftCodeDb/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatesRequireMatchingETag(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Versioned Bank",
+		SwiftCode:   "VERSPLPWKRK",
+		CountryCode: "PL",
+		BankType:    models.BankType(false),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "VERSPLPWKRK")
+
+	getResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(getResp, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/VERSPLPWKRK", nil))
+	assert.Equal(t, http.StatusOK, getResp.Code)
+	etag := getResp.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	notModifiedReq := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/VERSPLPWKRK", nil)
+	notModifiedReq.Header.Set("If-None-Match", etag)
+	notModifiedResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(notModifiedResp, notModifiedReq)
+	assert.Equal(t, http.StatusNotModified, notModifiedResp.Code)
+
+	patch := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/v1/swift-codes/VERSPLPWKRK", strings.NewReader(`{"address": "NEW STREET"}`))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp := httptest.NewRecorder()
+		handler.HandleSwiftCodes(resp, req)
+		return resp
+	}
+	assert.Equal(t, http.StatusPreconditionRequired, patch("").Code)
+
+	patchResp := patch(etag)
+	assert.Equal(t, http.StatusOK, patchResp.Code, patchResp.Body.String())
+	assert.NotEqual(t, etag, patchResp.Header().Get("ETag"))
+	assert.Contains(t, patchResp.Body.String(), `"NEW STREET"`)
+
+	assert.Equal(t, http.StatusPreconditionFailed, patch(etag).Code)
+
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/VERSPLPWKRK", nil)
+	deleteReq.Header.Set("If-Match", etag)
+	deleteResp := httptest.NewRecorder()
+	handler.HandleSwiftCodes(deleteResp, deleteReq)
+	assert.Equal(t, http.StatusPreconditionFailed, deleteResp.Code)
+}
+
+func TestHeadquarterETagChangesWithItsBranches(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+
+	err := store.InsertCountryWithValidation(queries, db.CreateCountryParams{CountryCode: "PL", CountryName: "POLAND"})
+	assert.NoError(t, err)
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Tagged Bank",
+		SwiftCode:   "ETAGPLPWXXX",
+		CountryCode: "PL",
+		BankType:    models.BankType(true),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "ETAGPLPWXXX")
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/ETAGPLPWXXX", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp := httptest.NewRecorder()
+		handler.HandleSwiftCodes(resp, req)
+		return resp
+	}
+	etag := get("").Header().Get("ETag")
+	assert.Equal(t, http.StatusNotModified, get(etag).Code)
+
+	err = store.InsertBankWithValidation(context.Background(), queries, db.CreateBankParams{
+		BankName:    "Tagged Bank Branch",
+		SwiftCode:   "ETAGPLPWKRK",
+		CountryCode: "PL",
+		BankType:    models.BankType(false),
+	}, store.AuditMetadata{})
+	assert.NoError(t, err)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "ETAGPLPWKRK")
+	handler.cache.invalidate(store.Change{SwiftCode: "ETAGPLPWKRK", CountryCode: "PL"})
+
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.Contains(t, changed.Body.String(), "ETAGPLPWKRK")
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestETagMatching(t *testing.T) {
+	for _, weak := range []bool{true, false} {
+		assert.True(t, etagMatches(`"1-2"`, `"1-2"`, weak))
+		assert.True(t, etagMatches("*", `"1-2"`, weak))
+		assert.False(t, etagMatches(`"1-1"`, `"1-2"`, weak))
+	}
+	assert.True(t, etagMatches(`"1-1", W/"1-2"`, `"1-2"`, true))
+	assert.False(t, etagMatches(`"1-1", W/"1-2"`, `"1-2"`, false), "If-Match never matches weak tags")
+}
+
+func TestUpdatesCannotOverlapAnotherValidityWindow(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	handler := NewBankHandler(dbConn)
+	queries := db.New(dbConn)
+	defer queries.DeleteBankBySwiftCode(context.Background(), "WINDPLPWKRK")
+
+	create := func(extra string) {
+		bankJSON := `{"swiftCode": "WINDPLPWKRK", "bankName": "Window Bank", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": false` + extra + `}`
+		resp := httptest.NewRecorder()
+		handler.CreateBank(resp, httptest.NewRequest(http.MethodPost, "/v1/swift-codes", strings.NewReader(bankJSON)))
+		assert.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	}
+	create("")
+	create(`, "validFrom": "` + time.Now().AddDate(0, 1, 0).Format(time.DateOnly) + `"`)
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/v1/swift-codes/WINDPLPWKRK", strings.NewReader(body))
+		req.Header.Set("If-Match", "*")
+		resp := httptest.NewRecorder()
+		handler.HandleSwiftCodes(resp, req)
+		return resp
+	}
+	extended := patch(`{"validTo": "` + time.Now().AddDate(0, 2, 0).Format(time.DateOnly) + `"}`)
+	assert.Equal(t, http.StatusConflict, extended.Code, "today's window would run into the future one")
+	shortened := patch(`{"validTo": "` + time.Now().AddDate(0, 0, 7).Format(time.DateOnly) + `"}`)
+	assert.Equal(t, http.StatusOK, shortened.Code, shortened.Body.String())
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+const (
+	ColumnCountryCode = "countryISO2"
+	ColumnSwiftCode   = "swiftCode"
+	ColumnBankName    = "bankName"
+	ColumnAddress     = "address"
+	ColumnCountryName = "countryName"
+	ColumnValidFrom   = "validFrom"
+	ColumnValidTo     = "validTo"
+)
+
+var RequiredColumns = []string{ColumnCountryCode, ColumnSwiftCode, ColumnBankName, ColumnCountryName}
+
+var DefaultAliases = map[string][]string{
+	ColumnCountryCode: {"COUNTRY ISO2 CODE", "COUNTRY ISO2", "COUNTRY CODE", "ISO2"},
+	ColumnSwiftCode:   {"SWIFT CODE", "SWIFT", "BIC", "BIC CODE"},
+	ColumnBankName:    {"NAME", "BANK NAME", "INSTITUTION NAME"},
+	ColumnAddress:     {"ADDRESS", "BANK ADDRESS"},
+	ColumnCountryName: {"COUNTRY NAME", "COUNTRY"},
+	ColumnValidFrom:   {"VALID FROM", "EFFECTIVE FROM", "ACTIVATION DATE"},
+	ColumnValidTo:     {"VALID TO", "EFFECTIVE TO", "DEACTIVATION DATE"},
+}
+
+var candidateDelimiters = []rune{',', ';', '\t', '|'}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type CSVOptions struct {
+	// Delimiter is detected from the header row when left as zero.
+	Delimiter rune
+	// Aliases extends DefaultAliases with additional header names per column.
+	Aliases map[string][]string
+}
+
+type CSVReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func NewCSVReader(r io.Reader, opts CSVOptions) (*CSVReader, error) {
+	buffered := bufio.NewReaderSize(r, 64*1024)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	delimiter := opts.Delimiter
+	if delimiter == 0 {
+		delimiter = detectDelimiter(peekLine(buffered))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("cannot read header: file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header: %s", err.Error())
+	}
+
+	columns, err := mapColumns(header, mergeAliases(opts.Aliases))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVReader{reader: reader, columns: columns}, nil
+}
+
+func (c *CSVReader) Read() (models.Bank, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return models.Bank{}, err
+	}
+	c.row++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return models.Bank{}, &RowError{Row: c.row, Err: err}
+	}
+	if err != nil {
+		return models.Bank{}, err
+	}
+
+	validFrom, err := c.date(record, ColumnValidFrom)
+	if err != nil {
+		return models.Bank{}, &RowError{Row: c.row, Err: err}
+	}
+	validTo, err := c.date(record, ColumnValidTo)
+	if err != nil {
+		return models.Bank{}, &RowError{Row: c.row, Err: err}
+	}
+
+	return models.Bank{
+		CountryCode: c.field(record, ColumnCountryCode),
+		SwiftCode:   c.field(record, ColumnSwiftCode),
+		BankName:    c.field(record, ColumnBankName),
+		Address:     c.field(record, ColumnAddress),
+		CountryName: c.field(record, ColumnCountryName),
+		ValidFrom:   validFrom,
+		ValidTo:     validTo,
+	}, nil
+}
+
+func (c *CSVReader) Row() int {
+	return c.row
+}
+
+func (c *CSVReader) field(record []string, column string) string {
+	index, ok := c.columns[column]
+	if !ok || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// date returns nil for a missing or empty column.
+func (c *CSVReader) date(record []string, column string) (*models.Date, error) {
+	value := c.field(record, column)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := models.ParseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func mapColumns(header []string, aliases map[string][]string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := normalizeHeader(name)
+		if _, exists := positions[key]; !exists {
+			positions[key] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	for column, names := range aliases {
+		for _, name := range append([]string{column}, names...) {
+			if index, ok := positions[normalizeHeader(name)]; ok {
+				columns[column] = index
+				break
+			}
+		}
+	}
+
+	var missing []string
+	for _, column := range RequiredColumns {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+func mergeAliases(extra map[string][]string) map[string][]string {
+	merged := make(map[string][]string, len(DefaultAliases))
+	for column, names := range DefaultAliases {
+		merged[column] = append(append([]string{}, extra[column]...), names...)
+	}
+	return merged
+}
+
+// normalizeHeader makes "SWIFT CODE", "swift_code" and "swiftCode" compare equal.
+func normalizeHeader(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return sb.String()
+}
+
+func peekLine(r *bufio.Reader) string {
+	data, _ := r.Peek(r.Size())
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		data = data[:i]
+	}
+	return string(data)
+}
+
+func detectDelimiter(line string) rune {
+	counts := make(map[rune]int)
+	inQuotes := false
+	for _, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if !inQuotes {
+			counts[r]++
+		}
+	}
+
+	best := candidateDelimiters[0]
+	for _, candidate := range candidateDelimiters[1:] {
+		if counts[candidate] > counts[best] {
+			best = candidate
+		}
+	}
+	return best
+}
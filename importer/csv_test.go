@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_csv_reader_maps_columns_of_reference_file(t *testing.T) {
+	file, err := os.Open("../seeder/swift_codes.csv")
+	require.NoError(t, err)
+	defer file.Close()
+
+	reader, err := NewCSVReader(file, CSVOptions{})
+	require.NoError(t, err)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, models.Bank{
+		Address:     "HYRJA 3 RR. DRITAN HOXHA ND. 11 TIRANA, TIRANA, 1023",
+		BankName:    "UNITED BANK OF ALBANIA SH.A",
+		CountryCode: "AL",
+		CountryName: "ALBANIA",
+		SwiftCode:   "AAISALTRXXX",
+	}, bank)
+	require.Equal(t, 1, reader.Row())
+}
+
+func Test_csv_reader_handles_reordered_extra_columns_semicolons_and_bom(t *testing.T) {
+	input := "\xEF\xBB\xBFExtra;Country Name;Bank Name;SWIFT;Country ISO2 Code\n" +
+		"x;POLAND;\"BANK; POLSKA\";BPKOPLPWXXX;PL\n"
+
+	reader, err := NewCSVReader(strings.NewReader(input), CSVOptions{})
+	require.NoError(t, err)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, models.Bank{
+		BankName:    "BANK; POLSKA",
+		CountryCode: "PL",
+		CountryName: "POLAND",
+		SwiftCode:   "BPKOPLPWXXX",
+	}, bank)
+
+	_, err = reader.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func Test_csv_reader_uses_configured_aliases_and_delimiter(t *testing.T) {
+	input := "cc|code|institution name|land\nPL|BPKOPLPWXXX|PKO|POLAND\n"
+	aliases := map[string][]string{
+		ColumnCountryCode: {"cc"},
+		ColumnSwiftCode:   {"code"},
+		ColumnCountryName: {"land"},
+	}
+
+	reader, err := NewCSVReader(strings.NewReader(input), CSVOptions{Delimiter: '|', Aliases: aliases})
+	require.NoError(t, err)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "PL", bank.CountryCode)
+	require.Equal(t, "BPKOPLPWXXX", bank.SwiftCode)
+	require.Equal(t, "PKO", bank.BankName)
+	require.Equal(t, "POLAND", bank.CountryName)
+}
+
+func Test_csv_reader_lists_missing_required_columns(t *testing.T) {
+	input := "SWIFT CODE,ADDRESS\nBPKOPLPWXXX,WARSAW\n"
+
+	_, err := NewCSVReader(strings.NewReader(input), CSVOptions{})
+	require.EqualError(t, err, "missing required columns: countryISO2, bankName, countryName")
+}
+
+func Test_csv_reader_rejects_empty_file(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader(""), CSVOptions{})
+	require.Error(t, err)
+}
+
+func Test_detect_delimiter_ignores_quoted_separators(t *testing.T) {
+	require.Equal(t, ',', detectDelimiter(`"A;B;C",D,E`))
+	require.Equal(t, ';', detectDelimiter(`A;B;"C,D,E,F"`))
+	require.Equal(t, '\t', detectDelimiter("A\tB\tC"))
+	require.Equal(t, ',', detectDelimiter("SINGLE"))
+}
+
+func Test_csv_reader_reads_validity_dates(t *testing.T) {
+	input := "SWIFT CODE,NAME,COUNTRY ISO2 CODE,COUNTRY NAME,VALID FROM,VALID TO\n" +
+		"BPKOPLPWXXX,PKO,PL,POLAND,2026-01-01,\n" +
+		"BPKOPLPWKRK,PKO,PL,POLAND,01/01/2026,\n"
+
+	reader, err := NewCSVReader(strings.NewReader(input), CSVOptions{})
+	require.NoError(t, err)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "2026-01-01", bank.ValidFrom.String())
+	require.Nil(t, bank.ValidTo)
+
+	_, err = reader.Read()
+	var rowErr *RowError
+	require.True(t, errors.As(err, &rowErr))
+	require.ErrorIs(t, err, models.ErrInvalidDate)
+	require.Equal(t, 2, rowErr.Row)
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// Reader yields banks one at a time and returns io.EOF when the input is exhausted.
+// Errors wrapped in RowError concern a single row and reading may continue after them;
+// any other error means the input cannot be read further.
+type Reader interface {
+	Read() (models.Bank, error)
+	Row() int
+}
+
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err.Error())
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv", ".txt":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", fmt.Errorf("cannot detect format of %q, use one of: %s, %s, %s", path, FormatCSV, FormatJSON, FormatNDJSON)
+}
+
+// NewReader returns a Reader for format whose rows are counted in the importer_rows_total metric.
+func NewReader(r io.Reader, format string, opts CSVOptions) (Reader, error) {
+	var reader Reader
+	var err error
+	switch format {
+	case FormatCSV:
+		reader, err = NewCSVReader(r, opts)
+	case FormatJSON:
+		reader, err = NewJSONReader(r)
+	case FormatNDJSON:
+		reader = NewNDJSONReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return countingReader{Reader: reader, format: format}, nil
+}
+
+func trimBank(bank models.Bank) models.Bank {
+	bank.Address = strings.TrimSpace(bank.Address)
+	bank.BankName = strings.TrimSpace(bank.BankName)
+	bank.CountryCode = strings.TrimSpace(bank.CountryCode)
+	bank.CountryName = strings.TrimSpace(bank.CountryName)
+	bank.SwiftCode = strings.TrimSpace(bank.SwiftCode)
+	return bank
+}
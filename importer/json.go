@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+type JSONReader struct {
+	decoder *json.Decoder
+	row     int
+	done    bool
+}
+
+func NewJSONReader(r io.Reader) (*JSONReader, error) {
+	decoder := json.NewDecoder(skipBOM(r))
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil, errors.New("cannot read json: file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read json: %s", err.Error())
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("cannot read json: expected an array of banks")
+	}
+
+	return &JSONReader{decoder: decoder}, nil
+}
+
+func (j *JSONReader) Read() (models.Bank, error) {
+	if j.done {
+		return models.Bank{}, io.EOF
+	}
+	if !j.decoder.More() {
+		j.done = true
+		if _, err := j.decoder.Token(); err != nil {
+			return models.Bank{}, fmt.Errorf("cannot read json: %s", err.Error())
+		}
+		return models.Bank{}, io.EOF
+	}
+
+	j.row++
+	var bank models.Bank
+	err := j.decoder.Decode(&bank)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) || errors.Is(err, models.ErrInvalidDate) {
+		return models.Bank{}, &RowError{Row: j.row, Err: err}
+	}
+	if err != nil {
+		j.done = true
+		return models.Bank{}, fmt.Errorf("cannot read json: %s", err.Error())
+	}
+
+	return trimBank(bank), nil
+}
+
+func (j *JSONReader) Row() int {
+	return j.row
+}
+
+type NDJSONReader struct {
+	reader *bufio.Reader
+	row    int
+}
+
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	return &NDJSONReader{reader: skipBOM(r)}
+}
+
+func (n *NDJSONReader) Read() (models.Bank, error) {
+	for {
+		line, err := n.reader.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
+			return models.Bank{}, io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return models.Bank{}, err
+		}
+		n.row++
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var bank models.Bank
+		if err := json.Unmarshal(line, &bank); err != nil {
+			return models.Bank{}, &RowError{Row: n.row, Err: err}
+		}
+		return trimBank(bank), nil
+	}
+}
+
+func (n *NDJSONReader) Row() int {
+	return n.row
+}
+
+func skipBOM(r io.Reader) *bufio.Reader {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+	return buffered
+}
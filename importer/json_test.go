@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_json_reader_reads_array_of_banks(t *testing.T) {
+	input := `[
+		{"swiftCode": "BPKOPLPWXXX", "bankName": "PKO", "countryISO2": "PL", "countryName": "POLAND", "address": " WARSAW "},
+		{"swiftCode": "BPKOPLPWKRK", "bankName": "PKO KRAKOW", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": false}
+	]`
+
+	reader, err := NewJSONReader(strings.NewReader(input))
+	require.NoError(t, err)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, models.Bank{
+		Address:     "WARSAW",
+		BankName:    "PKO",
+		CountryCode: "PL",
+		CountryName: "POLAND",
+		SwiftCode:   "BPKOPLPWXXX",
+	}, bank)
+
+	bank, err = reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "BPKOPLPWKRK", bank.SwiftCode)
+	require.Equal(t, 2, reader.Row())
+
+	_, err = reader.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func Test_json_reader_reports_wrong_field_type_as_row_error(t *testing.T) {
+	input := `[{"swiftCode": 12}, {"swiftCode": "BPKOPLPWXXX"}]`
+
+	reader, err := NewJSONReader(strings.NewReader(input))
+	require.NoError(t, err)
+
+	_, err = reader.Read()
+	var rowErr *RowError
+	require.True(t, errors.As(err, &rowErr))
+	require.Equal(t, 1, rowErr.Row)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "BPKOPLPWXXX", bank.SwiftCode)
+}
+
+func Test_json_reader_reports_invalid_date_as_row_error(t *testing.T) {
+	input := `[{"swiftCode": "BPKOPLPWKRK", "validFrom": "2026-13-01"}, {"swiftCode": "BPKOPLPWXXX", "validTo": "2027-01-01"}]`
+
+	reader, err := NewJSONReader(strings.NewReader(input))
+	require.NoError(t, err)
+
+	_, err = reader.Read()
+	var rowErr *RowError
+	require.True(t, errors.As(err, &rowErr))
+	require.ErrorIs(t, err, models.ErrInvalidDate)
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "2027-01-01", bank.ValidTo.String())
+}
+
+func Test_json_reader_stops_on_syntax_error(t *testing.T) {
+	reader, err := NewJSONReader(strings.NewReader(`[{"swiftCode": "A"}, {"swiftCode": `))
+	require.NoError(t, err)
+
+	_, err = reader.Read()
+	require.NoError(t, err)
+
+	_, err = reader.Read()
+	var rowErr *RowError
+	require.Error(t, err)
+	require.False(t, errors.As(err, &rowErr))
+
+	_, err = reader.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func Test_json_reader_rejects_non_array_input(t *testing.T) {
+	_, err := NewJSONReader(strings.NewReader(`{"swiftCode": "BPKOPLPWXXX"}`))
+	require.EqualError(t, err, "cannot read json: expected an array of banks")
+}
+
+func Test_ndjson_reader_skips_blank_lines_and_reports_line_numbers(t *testing.T) {
+	input := "\xEF\xBB\xBF{\"swiftCode\": \"BPKOPLPWXXX\", \"countryISO2\": \"PL\"}\n" +
+		"\n" +
+		"not json\n" +
+		"{\"swiftCode\": \"BPKOPLPWKRK\"}"
+
+	reader := NewNDJSONReader(strings.NewReader(input))
+
+	bank, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "BPKOPLPWXXX", bank.SwiftCode)
+	require.Equal(t, "PL", bank.CountryCode)
+
+	_, err = reader.Read()
+	var rowErr *RowError
+	require.True(t, errors.As(err, &rowErr))
+	require.Equal(t, 3, rowErr.Row)
+
+	bank, err = reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "BPKOPLPWKRK", bank.SwiftCode)
+
+	_, err = reader.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func Test_detect_format_by_extension(t *testing.T) {
+	tests := []struct {
+		path   string
+		format string
+	}{
+		{path: "swift_codes.csv", format: FormatCSV},
+		{path: "export.JSON", format: FormatJSON},
+		{path: "/tmp/export.ndjson", format: FormatNDJSON},
+		{path: "export.jsonl", format: FormatNDJSON},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			format, err := DetectFormat(test.path)
+			require.NoError(t, err)
+			require.Equal(t, test.format, format)
+		})
+	}
+
+	_, err := DetectFormat("export.xml")
+	require.Error(t, err)
+}
+
+func Test_new_reader_rejects_unknown_format(t *testing.T) {
+	_, err := NewReader(strings.NewReader(""), "xml", CSVOptions{})
+	require.EqualError(t, err, `unsupported format "xml"`)
+}
@@ -0,0 +1,28 @@
+package importer
+
+import (
+	"errors"
+
+	metrics "github.com/mateuszkochelski/SwiftCodeDb/metrics"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+var importedRows = metrics.Default.NewCounterVec("importer_rows_total", "Rows read by importers, by format and whether they parsed.", "format", "result")
+
+// countingReader counts the rows a Reader yields and rejects.
+type countingReader struct {
+	Reader
+	format string
+}
+
+func (c countingReader) Read() (models.Bank, error) {
+	bank, err := c.Reader.Read()
+	var rowErr *RowError
+	switch {
+	case err == nil:
+		importedRows.Inc(c.format, "parsed")
+	case errors.As(err, &rowErr):
+		importedRows.Inc(c.format, "invalid")
+	}
+	return bank, err
+}
@@ -0,0 +1,278 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minRefreshInterval bounds how often a remote key set is fetched again because a token named an unknown key.
+	minRefreshInterval = time.Minute
+	// minRSAKeyBits is the smallest RSA modulus tokens are accepted under; smaller keys are skipped.
+	minRSAKeyBits = 2048
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySetError reports that the key set could not be loaded, as opposed to a token failing validation.
+type KeySetError struct {
+	Err error
+}
+
+func (e *KeySetError) Error() string {
+	return "loading key set: " + e.Err.Error()
+}
+
+func (e *KeySetError) Unwrap() error {
+	return e.Err
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type verificationKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// KeySet holds the public keys tokens are verified with, indexed by key ID. Keys loaded from a URL are
+// fetched again when a token names a key the set does not know, so the issuer can rotate keys.
+type KeySet struct {
+	source string
+
+	mu          sync.Mutex
+	keys        map[string]verificationKey
+	lastRefresh time.Time
+	// refreshing is closed when the fetch in flight completes; tokens naming unknown keys meanwhile wait for it.
+	refreshing chan struct{}
+}
+
+// ParseKeySet reads a JWKS document. Keys that are not RS256 or ES256 signing keys are skipped.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	keys, err := parseKeys(data)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+// LoadKeySet reads a JWKS document from a file path or an http(s) URL.
+func LoadKeySet(ctx context.Context, source string) (*KeySet, error) {
+	set := &KeySet{source: source}
+	if err := set.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// key looks up kid, fetching the set again when it is unknown. The fetch runs without holding the lock, so
+// tokens naming known keys are not held up by it, and concurrent lookups share a single fetch. A failed
+// fetch is reported as ErrUnknownKey as well as a KeySetError, since the token still names no known key.
+func (s *KeySet) key(ctx context.Context, kid string) (verificationKey, error) {
+	s.mu.Lock()
+	if key, ok := s.keys[kid]; ok {
+		s.mu.Unlock()
+		return key, nil
+	}
+	if !isURL(s.source) {
+		s.mu.Unlock()
+		return verificationKey{}, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+
+	done := s.refreshing
+	if done == nil {
+		if time.Since(s.lastRefresh) < minRefreshInterval {
+			s.mu.Unlock()
+			return verificationKey{}, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+		}
+		done = make(chan struct{})
+		s.refreshing = done
+		s.lastRefresh = time.Now()
+		s.mu.Unlock()
+
+		// The fetch outlives a caller that gives up, as other lookups may be waiting for it.
+		keys, err := s.load(context.WithoutCancel(ctx))
+		s.mu.Lock()
+		if err == nil {
+			s.keys = keys
+		}
+		s.refreshing = nil
+		close(done)
+		s.mu.Unlock()
+		if err != nil {
+			return verificationKey{}, fmt.Errorf("%w %q: %w", ErrUnknownKey, kid, err)
+		}
+	} else {
+		s.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return verificationKey{}, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return verificationKey{}, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+}
+
+func (s *KeySet) refresh(ctx context.Context) error {
+	keys, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.lastRefresh = time.Now()
+	return nil
+}
+
+// load reads and parses the key set from its source without touching the keys in use.
+func (s *KeySet) load(ctx context.Context) (map[string]verificationKey, error) {
+	var data []byte
+	var err error
+	if isURL(s.source) {
+		data, err = fetch(ctx, s.source)
+	} else {
+		data, err = os.ReadFile(s.source)
+	}
+	if err != nil {
+		return nil, &KeySetError{Err: err}
+	}
+
+	keys, err := parseKeys(data)
+	if err != nil {
+		return nil, &KeySetError{Err: err}
+	}
+	return keys, nil
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func parseKeys(data []byte) (map[string]verificationKey, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parsing key set: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("parsing key %q: %w", jwk.Kid, err)
+		}
+		if key.alg == "" {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) verificationKey() (verificationKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		if jwk.Alg != "" && jwk.Alg != AlgorithmRS256 {
+			return verificationKey{}, nil
+		}
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return verificationKey{}, errors.New("invalid RSA exponent")
+		}
+		if n.BitLen() < minRSAKeyBits {
+			return verificationKey{}, nil
+		}
+		return verificationKey{alg: AlgorithmRS256, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if jwk.Crv != "P-256" || (jwk.Alg != "" && jwk.Alg != AlgorithmES256) {
+			return verificationKey{}, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return verificationKey{}, errors.New("invalid P-256 coordinates")
+		}
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return verificationKey{}, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return verificationKey{alg: AlgorithmES256, key: key}, nil
+	}
+	return verificationKey{}, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
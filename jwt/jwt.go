@@ -0,0 +1,169 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+
+	defaultLeeway = time.Minute
+)
+
+var (
+	ErrMalformedToken       = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrExpired              = errors.New("token expired")
+	ErrNotYetValid          = errors.New("token not yet valid")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+)
+
+// Claims is the decoded payload of a verified token.
+type Claims map[string]any
+
+// Subject returns the sub claim, or an empty string when it is missing.
+func (c Claims) Subject() string {
+	subject, _ := c["sub"].(string)
+	return subject
+}
+
+// Strings returns a claim holding either a single string or a list of strings.
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Validator verifies compact JWS tokens signed with RS256 or ES256 and checks their registered claims.
+type Validator struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewValidator returns a Validator that accepts tokens from issuer addressed to audience.
+func NewValidator(keys *KeySet, issuer string, audience string) *Validator {
+	return &Validator{keys: keys, issuer: issuer, audience: audience, leeway: defaultLeeway, now: time.Now}
+}
+
+// Validate checks the signature, exp, nbf, iss and aud of token and returns its claims.
+func (v *Validator) Validate(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var head header
+	if err := decodeSegment(parts[0], &head); err != nil {
+		return nil, err
+	}
+	if head.Alg != AlgorithmRS256 && head.Alg != AlgorithmES256 {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedAlgorithm, head.Alg)
+	}
+
+	key, err := v.keys.key(ctx, head.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != head.Alg {
+		return nil, fmt.Errorf("%w: key %q is not a %s key", ErrUnsupportedAlgorithm, head.Kid, head.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !verify(key.key, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Validator) checkClaims(claims Claims) error {
+	now := v.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: exp claim is required", ErrMalformedToken)
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(v.leeway)) {
+		return ErrExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.leeway).Before(time.Unix(int64(nbf), 0)) {
+		return ErrNotYetValid
+	}
+	if issuer, _ := claims["iss"].(string); issuer != v.issuer {
+		return fmt.Errorf("%w %q", ErrInvalidIssuer, issuer)
+	}
+	if !slices.Contains(claims.Strings("aud"), v.audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+func verify(key crypto.PublicKey, signed string, signature []byte) bool {
+	digest := sha256.Sum256([]byte(signed))
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key, digest[:], r, s)
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "alg": AlgorithmRS256, "use": "sig",
+		"n": encode(key.N.Bytes()), "e": encode(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": encode(key.X.FillBytes(make([]byte, 32))), "y": encode(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func keySetJSON(t *testing.T, keys ...map[string]string) []byte {
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+func sign(t *testing.T, alg string, kid string, key crypto.Signer, claims map[string]any) string {
+	head, err := json.Marshal(header{Alg: alg, Kid: kid, Typ: "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := encode(head) + "." + encode(payload)
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + encode(signature)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":   "alice",
+		"iss":   "https://idp.example",
+		"aud":   []string{"swift-codes", "other"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": "editor",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys, err := ParseKeySet(keySetJSON(t, rsaJWK("rsa", rsaKey), ecJWK("ec", ecKey)))
+	require.NoError(t, err)
+	validator := NewValidator(keys, "https://idp.example", "swift-codes")
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{name: "RS256", token: sign(t, AlgorithmRS256, "rsa", rsaKey, validClaims())},
+		{name: "ES256", token: sign(t, AlgorithmES256, "ec", ecKey, validClaims())},
+		{name: "single audience", token: sign(t, AlgorithmRS256, "rsa", rsaKey, with("aud", "swift-codes"))},
+		{name: "expired", token: sign(t, AlgorithmRS256, "rsa", rsaKey, with("exp", time.Now().Add(-time.Hour).Unix())), err: ErrExpired},
+		{name: "missing exp", token: sign(t, AlgorithmRS256, "rsa", rsaKey, with("exp", nil)), err: ErrMalformedToken},
+		{name: "not yet valid", token: sign(t, AlgorithmRS256, "rsa", rsaKey, with("nbf", time.Now().Add(time.Hour).Unix())), err: ErrNotYetValid},
+		{name: "wrong issuer", token: sign(t, AlgorithmRS256, "rsa", rsaKey, with("iss", "https://evil.example")), err: ErrInvalidIssuer},
+		{name: "wrong audience", token: sign(t, AlgorithmRS256, "rsa", rsaKey, with("aud", "billing")), err: ErrInvalidAudience},
+		{name: "signed by another key", token: sign(t, AlgorithmRS256, "rsa", otherKey, validClaims()), err: ErrInvalidSignature},
+		{name: "unknown key", token: sign(t, AlgorithmRS256, "missing", rsaKey, validClaims()), err: ErrUnknownKey},
+		{name: "algorithm does not match key", token: sign(t, AlgorithmES256, "rsa", ecKey, validClaims()), err: ErrUnsupportedAlgorithm},
+		{name: "unsigned", token: encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(`{}`)) + ".", err: ErrUnsupportedAlgorithm},
+		{name: "not a token", token: "abc", err: ErrMalformedToken},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := validator.Validate(context.Background(), tc.token)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", claims.Subject())
+			assert.Equal(t, []string{"editor"}, claims.Strings("roles"))
+		})
+	}
+}
+
+func TestLoadKeySetFromFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keySetJSON(t, ecJWK("ec", key)), 0o600))
+
+	keys, err := LoadKeySet(context.Background(), path)
+	require.NoError(t, err)
+	_, err = NewValidator(keys, "https://idp.example", "swift-codes").Validate(context.Background(), sign(t, AlgorithmES256, "ec", key, validClaims()))
+	assert.NoError(t, err)
+
+	_, err = LoadKeySet(context.Background(), filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestLoadKeySetFromURLPicksUpRotatedKeys(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	document := keySetJSON(t, rsaJWK("old", oldKey))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(document)
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL)
+	require.NoError(t, err)
+	validator := NewValidator(keys, "https://idp.example", "swift-codes")
+
+	document = keySetJSON(t, rsaJWK("old", oldKey), rsaJWK("new", newKey))
+	token := sign(t, AlgorithmRS256, "new", newKey, validClaims())
+	_, err = validator.Validate(context.Background(), token)
+	assert.ErrorIs(t, err, ErrUnknownKey, "refreshes are rate limited")
+
+	keys.lastRefresh = time.Time{}
+	_, err = validator.Validate(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+func TestRSAKeysBelowMinimumSizeAreSkipped(t *testing.T) {
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	keys, err := ParseKeySet(keySetJSON(t, rsaJWK("weak", weakKey)))
+	require.NoError(t, err)
+	_, err = NewValidator(keys, "https://idp.example", "swift-codes").Validate(context.Background(), sign(t, AlgorithmRS256, "weak", weakKey, validClaims()))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestFailedRefreshReportsUnknownKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write(keySetJSON(t, ecJWK("ec", key)))
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL)
+	require.NoError(t, err)
+	failing = true
+	keys.lastRefresh = time.Time{}
+
+	_, err = NewValidator(keys, "https://idp.example", "swift-codes").Validate(context.Background(), sign(t, AlgorithmES256, "rotated", key, validClaims()))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	var keyErr *KeySetError
+	assert.ErrorAs(t, err, &keyErr)
+}
+
+func TestRefreshDoesNotBlockKnownKeysAndIsShared(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		w.Write(keySetJSON(t, ecJWK("old", oldKey), ecJWK("new", newKey)))
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL)
+	require.NoError(t, err)
+	delete(keys.keys, "new")
+	keys.lastRefresh = time.Time{}
+	validator := NewValidator(keys, "https://idp.example", "swift-codes")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := validator.Validate(context.Background(), sign(t, AlgorithmES256, "new", newKey, validClaims()))
+			errs <- err
+		}()
+	}
+
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+	_, err = validator.Validate(context.Background(), sign(t, AlgorithmES256, "old", oldKey, validClaims()))
+	assert.NoError(t, err, "known keys verify while a refresh is in flight")
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), fetches.Load(), "concurrent lookups share one refresh")
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import "database/sql"
+
+// RegisterDBStats exposes the connection pool statistics of conn.
+func RegisterDBStats(r *Registry, conn *sql.DB) {
+	r.NewGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.", func() float64 {
+		return float64(conn.Stats().MaxOpenConnections)
+	})
+	r.NewGaugeFunc("db_open_connections", "Established connections, both in use and idle.", func() float64 {
+		return float64(conn.Stats().OpenConnections)
+	})
+	r.NewGaugeFunc("db_in_use_connections", "Connections currently in use.", func() float64 {
+		return float64(conn.Stats().InUse)
+	})
+	r.NewGaugeFunc("db_idle_connections", "Idle connections.", func() float64 {
+		return float64(conn.Stats().Idle)
+	})
+	r.NewCounterFunc("db_wait_count_total", "Connections waited for.", func() float64 {
+		return float64(conn.Stats().WaitCount)
+	})
+	r.NewCounterFunc("db_wait_duration_seconds_total", "Time spent waiting for a connection.", func() float64 {
+		return conn.Stats().WaitDuration.Seconds()
+	})
+	r.NewCounterFunc("db_max_idle_closed_total", "Connections closed due to SetMaxIdleConns.", func() float64 {
+		return float64(conn.Stats().MaxIdleClosed)
+	})
+	r.NewCounterFunc("db_max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.", func() float64 {
+		return float64(conn.Stats().MaxLifetimeClosed)
+	})
+}
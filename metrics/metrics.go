@@ -0,0 +1,250 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are latency histogram bounds in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Default is the registry the service's own metrics are kept in.
+var Default = NewRegistry()
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metrics in registration order.
+type Registry struct {
+	mu         sync.Mutex
+	names      map[string]bool
+	collectors []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText renders every metric in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// Handler serves the registry for scraping.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (f family) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, strings.ReplaceAll(f.help, "\n", " "), f.name, f.kind)
+}
+
+func (f family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// series renders a sample line; extra is appended to the family's labels, as le is for buckets.
+func (f family) series(w *bufio.Writer, suffix string, values []string, extra string, value float64) {
+	w.WriteString(f.name + suffix)
+	if len(values) > 0 || extra != "" {
+		w.WriteByte('{')
+		for i, label := range f.labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + escape(values[i]) + `"`)
+		}
+		if extra != "" {
+			if len(values) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extra)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// CounterVec is a set of counters told apart by label values.
+type CounterVec struct {
+	family
+	mu          sync.Mutex
+	values      map[string]float64
+	labelValues map[string][]string
+}
+
+func (r *Registry) NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		family:      family{name: name, help: help, kind: "counter", labels: labels},
+		values:      make(map[string]float64),
+		labelValues: make(map[string][]string),
+	}
+	r.register(name, c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	key := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.labelValues[key]; !ok {
+		c.labelValues[key] = slices.Clone(labelValues)
+	}
+	c.values[key] += delta
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w)
+	for _, key := range sortedKeys(c.labelValues) {
+		c.series(w, "", c.labelValues[key], "", c.values[key])
+	}
+}
+
+type histogram struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a set of histograms with shared bucket bounds, told apart by label values.
+type HistogramVec struct {
+	family
+	buckets    []float64
+	mu         sync.Mutex
+	histograms map[string]*histogram
+}
+
+func (r *Registry) NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		family:     family{name: name, help: help, kind: "histogram", labels: labels},
+		buckets:    slices.Sorted(slices.Values(buckets)),
+		histograms: make(map[string]*histogram),
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist, ok := h.histograms[key]
+	if !ok {
+		hist = &histogram{labels: slices.Clone(labelValues), counts: make([]uint64, len(h.buckets))}
+		h.histograms[key] = hist
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w)
+	for _, key := range sortedKeys(h.histograms) {
+		hist := h.histograms[key]
+		for i, bound := range h.buckets {
+			h.series(w, "_bucket", hist.labels, `le="`+formatFloat(bound)+`"`, float64(hist.counts[i]))
+		}
+		h.series(w, "_bucket", hist.labels, `le="+Inf"`, float64(hist.count))
+		h.series(w, "_sum", hist.labels, "", hist.sum)
+		h.series(w, "_count", hist.labels, "", float64(hist.count))
+	}
+}
+
+// funcMetric reads its value when scraped, for state kept elsewhere such as connection pool stats.
+type funcMetric struct {
+	family
+	value func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from value on every scrape.
+func (r *Registry) NewGaugeFunc(name string, help string, value func() float64) {
+	r.register(name, &funcMetric{family: family{name: name, help: help, kind: "gauge"}, value: value})
+}
+
+// NewCounterFunc registers a counter whose value is read from value on every scrape.
+func (r *Registry) NewCounterFunc(name string, help string, value func() float64) {
+	r.register(name, &funcMetric{family: family{name: name, help: help, kind: "counter"}, value: value})
+}
+
+func (m *funcMetric) write(w *bufio.Writer) {
+	m.writeHeader(w)
+	m.series(w, "", nil, "", m.value())
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("requests_total", "Requests served.", "route", "status")
+	latency := registry.NewHistogramVec("latency_seconds", "Request latency.", []float64{1, 0.1}, "route")
+	registry.NewGaugeFunc("pool_size", "Pool size.", func() float64 { return 4 })
+
+	requests.Inc("/v1/b", "200")
+	requests.Add(2, "/v1/a", "404")
+	requests.Inc("/v1/a", "404")
+	requests.Inc(`quote"d`, "500")
+	latency.Observe(0.05, "/v1/a")
+	latency.Observe(0.5, "/v1/a")
+	latency.Observe(3, "/v1/a")
+
+	var out bytes.Buffer
+	require.NoError(t, registry.WriteText(&out))
+	assert.Equal(t, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{route="/v1/a",status="404"} 3
+requests_total{route="/v1/b",status="200"} 1
+requests_total{route="quote\"d",status="500"} 1
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/v1/a",le="0.1"} 1
+latency_seconds_bucket{route="/v1/a",le="1"} 2
+latency_seconds_bucket{route="/v1/a",le="+Inf"} 3
+latency_seconds_sum{route="/v1/a"} 3.55
+latency_seconds_count{route="/v1/a"} 3
+# HELP pool_size Pool size.
+# TYPE pool_size gauge
+pool_size 4
+`, out.String())
+}
+
+func TestRegistryRejectsMisuse(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounterVec("requests_total", "Requests served.", "route")
+
+	assert.Panics(t, func() { registry.NewCounterVec("requests_total", "Again.") })
+	assert.Panics(t, func() { counter.Inc("/v1/a", "extra") })
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterFunc("events_total", "Events.", func() float64 { return 7 })
+
+	resp := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Header().Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, resp.Body.String(), "# TYPE events_total counter\nevents_total 7\n")
+}
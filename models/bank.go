@@ -1,20 +1,40 @@
 package models
 
 import (
+	"database/sql"
+	"regexp"
+	"time"
+
 	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
 )
 
+var swiftCodeFormat = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{5}$`)
+
 type Bank struct {
-	Address       string `json:"address"`
-	BankName      string `json:"bankName"`
-	CountryCode   string `json:"countryISO2"`
-	CountryName   string `json:"countryName,omitempty"`
-	IsHeadquarter bool   `json:"isHeadquarter"`
-	SwiftCode     string `json:"swiftCode"`
+	Address       string     `json:"address"`
+	BankName      string     `json:"bankName"`
+	CountryCode   string     `json:"countryISO2"`
+	CountryName   string     `json:"countryName,omitempty"`
+	IsHeadquarter bool       `json:"isHeadquarter"`
+	SwiftCode     string     `json:"swiftCode"`
+	ValidFrom     *Date      `json:"validFrom,omitempty"`
+	ValidTo       *Date      `json:"validTo,omitempty"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
 }
 
 func ConvertToBank[T any](row T) Bank {
 	switch r := any(row).(type) {
+	case db.Bank:
+		return Bank{
+			Address:       r.BankAddress.String,
+			BankName:      r.BankName,
+			CountryCode:   r.CountryCode,
+			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
+			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
+			DeletedAt:     deletedAt(r.DeletedAt),
+		}
 	case db.GetBankBySwiftCodeWithCountryRow:
 		return Bank{
 			Address:       r.BankAddress.String,
@@ -23,6 +43,9 @@ func ConvertToBank[T any](row T) Bank {
 			CountryName:   r.CountryName,
 			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
 			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
+			DeletedAt:     deletedAt(r.DeletedAt),
 		}
 	case db.GetBanksBranchesBySwiftCodePrefixRow:
 		return Bank{
@@ -31,6 +54,9 @@ func ConvertToBank[T any](row T) Bank {
 			CountryCode:   r.CountryCode,
 			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
 			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
+			DeletedAt:     deletedAt(r.DeletedAt),
 		}
 	case db.GetBanksByCountryCodeRow:
 		return Bank{
@@ -39,6 +65,41 @@ func ConvertToBank[T any](row T) Bank {
 			CountryCode:   r.CountryCode,
 			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
 			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
+			DeletedAt:     deletedAt(r.DeletedAt),
+		}
+	case db.GetBanksBySwiftCodesRow:
+		return Bank{
+			Address:       r.BankAddress.String,
+			BankName:      r.BankName,
+			CountryCode:   r.CountryCode,
+			CountryName:   r.CountryName,
+			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
+			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
+		}
+	case db.ListBanksPageRow:
+		return Bank{
+			Address:       r.BankAddress.String,
+			BankName:      r.BankName,
+			CountryCode:   r.CountryCode,
+			CountryName:   r.CountryName,
+			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
+			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
+		}
+	case db.SnapshotBank:
+		return Bank{
+			Address:       r.BankAddress.String,
+			BankName:      r.BankName,
+			CountryCode:   r.CountryCode,
+			IsHeadquarter: r.BankType == db.BankTypeHeadquarter,
+			SwiftCode:     r.SwiftCode,
+			ValidFrom:     dateOrNil(r.ValidFrom),
+			ValidTo:       dateOrNil(r.ValidTo),
 		}
 	default:
 		panic("Unsupported type for ConvertBank")
@@ -54,9 +115,33 @@ func ConvertToBanks[T any](rows []T) []Bank {
 	return banks
 }
 
+func deletedAt(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
 func BankType(isHeadquarer bool) db.BankType {
 	if isHeadquarer {
 		return db.BankTypeHeadquarter
 	}
 	return db.BankTypeBranch
 }
+
+// IsValidSwiftCode reports whether code is an 11 character BIC: bank and country letters followed by location and branch.
+func IsValidSwiftCode(code string) bool {
+	return swiftCodeFormat.MatchString(code)
+}
+
+// InstitutionPrefix returns the bank, country and location part shared by a headquarter and its branches.
+func InstitutionPrefix(swiftCode string) string {
+	if len(swiftCode) < 8 {
+		return swiftCode
+	}
+	return swiftCode[:8]
+}
+
+func HeadquarterSwiftCode(swiftCode string) string {
+	return InstitutionPrefix(swiftCode) + "XXX"
+}
@@ -3,17 +3,38 @@ package models
 import (
 	"database/sql"
 	"testing"
+	"time"
 
 	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestConvertToBank(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
 	tests := []struct {
 		name     string
 		input    any
 		expected Bank
 	}{
+		{
+			name: "Convert Bank",
+			input: db.Bank{
+				ID:            3,
+				BankAddress:   sql.NullString{String: "Deleted Address", Valid: true},
+				BankName:      "Deleted Bank",
+				CountryCode:   "PL",
+				BankType:      db.BankTypeBranch,
+				SwiftCode:     "DELETEPLKRK",
+				InstitutionID: 1,
+			},
+			expected: Bank{
+				Address:       "Deleted Address",
+				BankName:      "Deleted Bank",
+				CountryCode:   "PL",
+				IsHeadquarter: false,
+				SwiftCode:     "DELETEPLKRK",
+			},
+		},
 		{
 			name: "Convert GetBankBySwiftCodeWithCountryRow",
 			input: db.GetBankBySwiftCodeWithCountryRow{
@@ -67,6 +88,42 @@ func TestConvertToBank(t *testing.T) {
 				SwiftCode:     "COUNTRYUSXXX",
 			},
 		},
+		{
+			name: "Convert ListBanksPageRow",
+			input: db.ListBanksPageRow{
+				ID:          7,
+				BankAddress: sql.NullString{String: "Export Address", Valid: true},
+				BankName:    "Export Bank",
+				CountryCode: "PL",
+				CountryName: "POLAND",
+				BankType:    db.BankTypeHeadquarter,
+				SwiftCode:   "EXPORTPLXXX",
+			},
+			expected: Bank{
+				Address:       "Export Address",
+				BankName:      "Export Bank",
+				CountryCode:   "PL",
+				CountryName:   "POLAND",
+				IsHeadquarter: true,
+				SwiftCode:     "EXPORTPLXXX",
+			},
+		},
+		{
+			name: "Convert soft deleted Bank",
+			input: db.Bank{
+				BankName:    "Deleted Bank",
+				CountryCode: "PL",
+				BankType:    db.BankTypeBranch,
+				SwiftCode:   "DELETEPLKRK",
+				DeletedAt:   sql.NullTime{Time: deletedAt, Valid: true},
+			},
+			expected: Bank{
+				BankName:    "Deleted Bank",
+				CountryCode: "PL",
+				SwiftCode:   "DELETEPLKRK",
+				DeletedAt:   &deletedAt,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -120,3 +177,19 @@ func TestBankType(t *testing.T) {
 	assert.Equal(t, db.BankTypeHeadquarter, BankType(true))
 	assert.Equal(t, db.BankTypeBranch, BankType(false))
 }
+
+func TestIsValidSwiftCode(t *testing.T) {
+	assert.True(t, IsValidSwiftCode("AAISALTRXXX"))
+	assert.True(t, IsValidSwiftCode("BPKOPLPW123"))
+	assert.False(t, IsValidSwiftCode("BPKOPLPW"))
+	assert.False(t, IsValidSwiftCode("bpkoplpwxxx"))
+	assert.False(t, IsValidSwiftCode("1PKOPLPWXXX"))
+	assert.False(t, IsValidSwiftCode("BPKOPLPWXXXX"))
+}
+
+func TestHeadquarterSwiftCode(t *testing.T) {
+	assert.Equal(t, "BPKOPLPW", InstitutionPrefix("BPKOPLPWKRK"))
+	assert.Equal(t, "BPKOPLPWXXX", HeadquarterSwiftCode("BPKOPLPWKRK"))
+	assert.Equal(t, "BPKOPLPWXXX", HeadquarterSwiftCode("BPKOPLPWXXX"))
+	assert.Equal(t, "SHORTXXX", HeadquarterSwiftCode("SHORT"))
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidDate = errors.New("invalid date")
+
+// Date is a calendar day, written as YYYY-MM-DD in JSON and CSV.
+type Date struct {
+	time.Time
+}
+
+func ParseDate(value string) (Date, error) {
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w %q, expected YYYY-MM-DD", ErrInvalidDate, value)
+	}
+	return Date{Time: day}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(time.DateOnly)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDate, err.Error())
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// NullTime converts an optional date to the nullable column type used by db.
+func (d *Date) NullTime() sql.NullTime {
+	if d == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: d.Time, Valid: true}
+}
+
+func dateOrNil(value sql.NullTime) *Date {
+	if !value.Valid {
+		return nil
+	}
+	return &Date{Time: value.Time}
+}
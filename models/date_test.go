@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateJSONRoundTrip(t *testing.T) {
+	var bank Bank
+	err := json.Unmarshal([]byte(`{"swiftCode": "BPKOPLPWXXX", "validFrom": "2026-01-01"}`), &bank)
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-01-01", bank.ValidFrom.String())
+	assert.Nil(t, bank.ValidTo)
+
+	data, err := json.Marshal(bank)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"validFrom":"2026-01-01"`)
+	assert.NotContains(t, string(data), "validTo")
+}
+
+func TestDateRejectsOtherLayouts(t *testing.T) {
+	var bank Bank
+	err := json.Unmarshal([]byte(`{"validFrom": "2026-01-01T00:00:00Z"}`), &bank)
+	assert.ErrorIs(t, err, ErrInvalidDate)
+}
@@ -0,0 +1,33 @@
+package models
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// CompareBanks lists the fields that differ between two versions of a bank, named as in its JSON form.
+func CompareBanks(before, after Bank) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, beforeValue, afterValue any) {
+		if beforeValue != afterValue {
+			changes = append(changes, FieldChange{Field: field, Old: beforeValue, New: afterValue})
+		}
+	}
+
+	add("address", before.Address, after.Address)
+	add("bankName", before.BankName, after.BankName)
+	add("countryISO2", before.CountryCode, after.CountryCode)
+	add("countryName", before.CountryName, after.CountryName)
+	add("isHeadquarter", before.IsHeadquarter, after.IsHeadquarter)
+	add("validFrom", dateValue(before.ValidFrom), dateValue(after.ValidFrom))
+	add("validTo", dateValue(before.ValidTo), dateValue(after.ValidTo))
+	return changes
+}
+
+func dateValue(date *Date) any {
+	if date == nil {
+		return nil
+	}
+	return date.String()
+}
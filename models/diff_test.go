@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareBanksListsChangedFields(t *testing.T) {
+	validFrom, err := ParseDate("2026-04-01")
+	assert.NoError(t, err)
+	before := Bank{Address: "OLD STREET", BankName: "PKO", CountryCode: "PL", SwiftCode: "BPKOPLPWKRK"}
+	after := before
+	after.Address = "NEW STREET"
+	after.ValidFrom = &validFrom
+
+	assert.Equal(t, []FieldChange{
+		{Field: "address", Old: "OLD STREET", New: "NEW STREET"},
+		{Field: "validFrom", Old: nil, New: "2026-04-01"},
+	}, CompareBanks(before, after))
+	assert.Empty(t, CompareBanks(before, before))
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		decision, err := store.Take(context.Background(), "client", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 3, decision.Limit)
+		assert.Equal(t, i, decision.Remaining)
+	}
+
+	decision, err := store.Take(context.Background(), "client", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, decision.Reset)
+
+	other, err := store.Take(context.Background(), "other", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "buckets are kept per key")
+
+	now = now.Add(500 * time.Millisecond)
+	decision, err = store.Take(context.Background(), "client", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	now = now.Add(time.Hour)
+	decision, err = store.Take(context.Background(), "client", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, decision.Remaining, "refill stops at the burst size")
+}
+
+func TestMemoryStoreSweepsRefilledBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, err := store.Take(context.Background(), "idle", Limit{Rate: 1, Burst: 5})
+	require.NoError(t, err)
+	_, err = store.Take(context.Background(), "busy", Limit{Rate: 0.001, Burst: 5})
+	require.NoError(t, err)
+
+	now = now.Add(2 * sweepInterval)
+	_, err = store.Take(context.Background(), "new", Limit{Rate: 1, Burst: 5})
+	require.NoError(t, err)
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
+
+func TestLimitUnlimited(t *testing.T) {
+	assert.True(t, Limit{}.Unlimited())
+	assert.True(t, Limit{Rate: 1}.Unlimited())
+	assert.False(t, Limit{Rate: 1, Burst: 1}.Unlimited())
+}
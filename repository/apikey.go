@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+)
+
+const apiKeyPrefix = "swk_"
+
+// APIKeyRoles lists the roles a key can hold, from least to most privileged.
+var APIKeyRoles = []db.ApiKeyRole{db.ApiKeyRoleReader, db.ApiKeyRoleEditor, db.ApiKeyRoleAdmin}
+
+// RoleGrants reports whether a key holding role may do what required is needed for. Every role
+// includes the permissions of the roles listed before it.
+func RoleGrants(role db.ApiKeyRole, required db.ApiKeyRole) bool {
+	have := slices.Index(APIKeyRoles, role)
+	return have >= 0 && have >= slices.Index(APIKeyRoles, required)
+}
+
+// HashAPIKey returns the form an API key is stored and looked up in. Keys are random, so a plain digest suffices.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey generates a new key for name with the given role and stores its hash.
+// The plain key is returned only once.
+func CreateAPIKey(ctx context.Context, queries *db.Queries, name string, role db.ApiKeyRole) (string, db.ApiKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", db.ApiKey{}, err
+	}
+
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+	row, err := queries.CreateApiKey(ctx, db.CreateApiKeyParams{Name: name, KeyHash: HashAPIKey(key), Role: role})
+	if err != nil {
+		return "", db.ApiKey{}, err
+	}
+	return key, row, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
+)
+
+// AuditMetadata identifies who made a change and the request it came from.
+type AuditMetadata struct {
+	Actor     string
+	RequestID string
+}
+
+// RecordAudit appends an audit row using queries, so it commits or rolls back together with the change it describes.
+func RecordAudit(ctx context.Context, queries *db.Queries, meta AuditMetadata, operation db.AuditOperation, swiftCode string, before, after *models.Bank) error {
+	beforeData, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = queries.CreateBankAudit(ctx, db.CreateBankAuditParams{
+		Operation:  operation,
+		SwiftCode:  swiftCode,
+		BeforeData: beforeData,
+		AfterData:  afterData,
+		Actor:      meta.Actor,
+		RequestID:  meta.RequestID,
+	})
+	return err
+}
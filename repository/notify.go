@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+)
+
+// SwiftChangesChannel is the channel writes announce the banks they changed on, so that every
+// backend replica can drop what it has cached about them.
+const SwiftChangesChannel = "swift_changes"
+
+// Change names a SWIFT code a write touched and the country its bank is in afterwards, which is
+// what decides the cached lookups it makes stale. All stands for writes too broad to list, such as imports.
+type Change struct {
+	SwiftCode   string `json:"swiftCode,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	All         bool   `json:"all,omitempty"`
+}
+
+// NotifyChanges announces changes using queries. Inside a transaction the notifications are only
+// delivered once it commits, and not at all if it rolls back.
+func NotifyChanges(ctx context.Context, queries *db.Queries, changes ...Change) error {
+	for _, change := range changes {
+		payload, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+		if err := queries.NotifySwiftChange(ctx, string(payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseChange reads the payload of a notification sent by NotifyChanges.
+func ParseChange(payload string) (Change, error) {
+	var change Change
+	err := json.Unmarshal([]byte(payload), &change)
+	return change, err
+}
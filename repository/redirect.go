@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+)
+
+var ErrRedirectCycle = errors.New("redirect cycle")
+
+// ResolveRedirect follows bic_redirects from swiftCode and returns the last code of the chain,
+// which is swiftCode itself when it is not redirected.
+func ResolveRedirect(ctx context.Context, queries *db.Queries, swiftCode string) (string, error) {
+	visited := map[string]bool{swiftCode: true}
+	current := swiftCode
+	for {
+		redirect, err := queries.GetBicRedirect(ctx, current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return current, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if visited[redirect.NewSwiftCode] {
+			return "", fmt.Errorf("%w: %s redirects back to %s", ErrRedirectCycle, current, redirect.NewSwiftCode)
+		}
+		visited[redirect.NewSwiftCode] = true
+		current = redirect.NewSwiftCode
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
 )
 
 func InsertCountryWithValidation(queries *db.Queries, newCountry db.CreateCountryParams) error {
@@ -27,17 +29,71 @@ func InsertCountryWithValidation(queries *db.Queries, newCountry db.CreateCountr
 	return nil
 }
 
-func InsertBankWithValidation(queries *db.Queries, newBank db.CreateBankParams) error {
-	_, getError := queries.GetBankBySwiftCodeWithCountry(context.Background(), newBank.SwiftCode)
-	if getError == sql.ErrNoRows {
-		_, insertionError := queries.CreateBank(context.Background(), newBank)
-		if insertionError != nil {
-			return fmt.Errorf("insertion failed %s", insertionError.Error())
-		}
-		return nil
-	} else if getError != nil {
+// InsertBankWithValidation rejects a bank whose validity window overlaps a live row of the same code; an undated
+// row takes effect today. A dated row instead supersedes an open-ended one, which then ends the day the new row
+// starts and is audited as an update on behalf of meta.
+func InsertBankWithValidation(ctx context.Context, queries *db.Queries, newBank db.CreateBankParams, meta AuditMetadata) error {
+	startsOn := time.Now()
+	if newBank.ValidFrom.Valid {
+		startsOn = newBank.ValidFrom.Time
+	}
+	overlapping, getError := queries.CountOverlappingBanks(ctx, db.CountOverlappingBanksParams{
+		SwiftCode:  newBank.SwiftCode,
+		ValidFrom:  startsOn,
+		ValidTo:    newBank.ValidTo,
+		Supersedes: newBank.ValidFrom.Valid,
+	})
+	if getError != nil {
 		return fmt.Errorf("query error %s", getError)
 	}
+	if overlapping > 0 {
+		return errors.New("there was bank with existing code in database")
+	}
+
+	if newBank.ValidFrom.Valid {
+		closed, closeError := queries.CloseBankValidity(ctx, db.CloseBankValidityParams{
+			ValidTo:   newBank.ValidFrom,
+			SwiftCode: newBank.SwiftCode,
+		})
+		if closeError != nil {
+			return fmt.Errorf("query error %s", closeError)
+		}
+		for _, row := range closed {
+			after := models.ConvertToBank(row)
+			before := after
+			before.ValidTo = nil
+			if auditError := RecordAudit(ctx, queries, meta, db.AuditOperationUpdate, row.SwiftCode, &before, &after); auditError != nil {
+				return fmt.Errorf("audit failed %s", auditError.Error())
+			}
+		}
+	}
+
+	institution, insertionError := queries.UpsertInstitution(ctx, models.InstitutionPrefix(newBank.SwiftCode))
+	if insertionError != nil {
+		return fmt.Errorf("institution insertion failed %s", insertionError.Error())
+	}
+	newBank.InstitutionID = institution.ID
+	_, insertionError = queries.CreateBank(ctx, newBank)
+	if insertionError != nil {
+		return fmt.Errorf("insertion failed %s", insertionError.Error())
+	}
+	return nil
+}
+
+// ExecTx runs fn with queries bound to a single transaction, committing only when fn succeeds.
+func ExecTx(ctx context.Context, conn *sql.DB, fn func(*db.Queries) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = fn(db.New(tx))
+	if err != nil {
+		if rollbackError := tx.Rollback(); rollbackError != nil {
+			return fmt.Errorf("%s, rollback failed %s", err.Error(), rollbackError.Error())
+		}
+		return err
+	}
 
-	return errors.New("there was bank with existing code in database")
+	return tx.Commit()
 }
@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	db "github.com/mateuszkochelski/SwiftCodeDb/db/sqlc"
-	"github.com/mateuszkochelski/SwiftCodeDb/util"
+	models "github.com/mateuszkochelski/SwiftCodeDb/models"
 	"github.com/stretchr/testify/require"
 )
 
@@ -212,27 +212,39 @@ func Test_validate_data_looking_for_errors_at_all(t *testing.T) {
 	})
 }
 
-func Test_get_data_from_record_returns_wrong_number_of_column_error(t *testing.T) {
-	var columns []string
-	const invalid_columns_error_message = "invalid numbers of column in record"
-	for i := 0; i < 7; i++ {
-		columns = append(columns, util.RandomString(10))
-	}
-	_, _, err := getDataFromRecord(columns)
-	require.EqualError(t, err, invalid_columns_error_message)
+func Test_alias_flag_rejects_unknown_column_and_malformed_value(t *testing.T) {
+	aliases := aliasFlag{}
+	require.NoError(t, aliases.Set("swiftCode=BIC11"))
+	require.Equal(t, []string{"BIC11"}, aliases["swiftCode"])
+
+	require.Error(t, aliases.Set("timezone=TIME ZONE"))
+	require.Error(t, aliases.Set("swiftCode"))
+	require.Error(t, aliases.Set("=SWIFT"))
+}
+
+func Test_parse_delimiter(t *testing.T) {
+	delimiter, err := parseDelimiter("")
+	require.NoError(t, err)
+	require.Equal(t, rune(0), delimiter)
+
+	delimiter, err = parseDelimiter("tab")
+	require.NoError(t, err)
+	require.Equal(t, '\t', delimiter)
 
-	columns = append(columns, util.RandomString(2))
-	_, _, err = getDataFromRecord(columns)
-	require.NotContains(t, err.Error(), invalid_columns_error_message)
+	delimiter, err = parseDelimiter(";")
+	require.NoError(t, err)
+	require.Equal(t, ';', delimiter)
 
-	columns = append(columns, util.RandomString(2))
-	_, _, err = getDataFromRecord(columns)
-	require.EqualError(t, err, invalid_columns_error_message)
+	_, err = parseDelimiter(";;")
+	require.Error(t, err)
 }
 
 func Test_get_data_from_record_returning_country_and_bank_given_valid_data(t *testing.T) {
-	testRecord := []string{
-		"AL", "12345678XXX", "BIC11", "Bank", "", "cravow", "ALBANIA", "Pacific",
+	testRecord := models.Bank{
+		CountryCode: "AL",
+		SwiftCode:   "12345678XXX",
+		BankName:    "Bank",
+		CountryName: "ALBANIA",
 	}
 	bank, country, err := getDataFromRecord(testRecord)
 	require.NoError(t, err)
@@ -243,3 +255,26 @@ func Test_get_data_from_record_returning_country_and_bank_given_valid_data(t *te
 	require.Equal(t, country.CountryCode, "AL")
 	require.Equal(t, country.CountryName, "ALBANIA")
 }
+
+func Test_get_data_from_record_keeps_future_validity_and_rejects_inverted_range(t *testing.T) {
+	validFrom, err := models.ParseDate("2027-01-01")
+	require.NoError(t, err)
+	validTo, err := models.ParseDate("2026-01-01")
+	require.NoError(t, err)
+	testRecord := models.Bank{
+		CountryCode: "AL",
+		SwiftCode:   "12345678XXX",
+		BankName:    "Bank",
+		CountryName: "ALBANIA",
+		ValidFrom:   &validFrom,
+	}
+
+	bank, _, err := getDataFromRecord(testRecord)
+	require.NoError(t, err)
+	require.Equal(t, sql.NullTime{Time: validFrom.Time, Valid: true}, bank.ValidFrom)
+	require.False(t, bank.ValidTo.Valid)
+
+	testRecord.ValidTo = &validTo
+	_, _, err = getDataFromRecord(testRecord)
+	require.EqualError(t, err, "invalid data: valid to must be after valid from")
+}
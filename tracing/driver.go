@@ -0,0 +1,208 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer query spans are started with.
+const instrumentationName = "github.com/mateuszkochelski/SwiftCodeDb/tracing"
+
+// OpenDB opens a database like sql.Open, with a client span around every statement run on it as a child
+// of the span in its context, named after the sqlc query. A query's span lasts until its rows are closed,
+// so it covers reading them. Spans go to the tracer provider of their parent, so statements run outside a
+// traced request cost nothing extra.
+func OpenDB(driverName, dataSourceName string) (*sql.DB, error) {
+	// sql.Open only looks the driver up, it does not connect.
+	probe, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	inner := probe.Driver()
+	probe.Close()
+
+	var connector driver.Connector = dsnConnector{dsn: dataSourceName, driver: inner}
+	if withConnector, ok := inner.(driver.DriverContext); ok {
+		if connector, err = withConnector.OpenConnector(dataSourceName); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(tracedConnector{inner: connector}), nil
+}
+
+// dsnConnector connects through drivers that cannot parse their data source name up front.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type tracedConnector struct {
+	inner driver.Connector
+}
+
+func (c tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.inner.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tracedConn{Conn: conn}, nil
+}
+
+func (c tracedConnector) Driver() driver.Driver {
+	return c.inner.Driver()
+}
+
+// tracedConn passes everything through to the driver's connection, tracing what it runs directly.
+// Prepared statements are not traced, sqlc does not use them.
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	endQuery(span, err)
+	return result, err
+}
+
+func (c tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		endQuery(span, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (c tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts != (driver.TxOptions{}) {
+		return nil, errors.New("tracing: driver does not support transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+func (c tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// tracedRows ends the span of its query once the caller is done reading, which is when database/sql
+// closes them: on rows.Close, after the last row, or in Row.Scan.
+type tracedRows struct {
+	driver.Rows
+	span trace.Span
+	err  error
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return err
+}
+
+func (r *tracedRows) HasNextResultSet() bool {
+	multi, ok := r.Rows.(driver.RowsNextResultSet)
+	return ok && multi.HasNextResultSet()
+}
+
+func (r *tracedRows) NextResultSet() error {
+	if multi, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return multi.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	if r.err == nil {
+		r.err = err
+	}
+	endQuery(r.span, r.err)
+	return err
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.IsRecording() {
+		return ctx, parent
+	}
+	name := queryName(query)
+	return parent.TracerProvider().Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(name)),
+	)
+}
+
+func endQuery(span trace.Span, err error) {
+	if !span.IsRecording() {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryName reads the name sqlc puts in the comment heading each query, e.g. "-- name: GetBank :one".
+func queryName(query string) string {
+	line, _, _ := strings.Cut(query, "\n")
+	fields := strings.Fields(strings.TrimPrefix(line, "-- name:"))
+	if !strings.HasPrefix(line, "-- name:") || len(fields) == 0 {
+		return "query"
+	}
+	return fields[0]
+}
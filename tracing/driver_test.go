@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{remaining: 2}, nil
+}
+
+func (fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return nil, errors.New("connection reset")
+}
+
+type fakeRows struct{ remaining int }
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	r.remaining--
+	dest[0] = int64(r.remaining)
+	return nil
+}
+
+func init() {
+	sql.Register("tracing-fake", fakeDriver{})
+}
+
+func TestQuerySpanEndsWhenRowsAreClosed(t *testing.T) {
+	conn, err := OpenDB("tracing-fake", "")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, server := provider.Tracer("test").Start(context.Background(), "GET /v1/swift-codes/")
+
+	rows, err := conn.QueryContext(ctx, "-- name: ListBanks :many\nSELECT id FROM banks")
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Ended(), "the span stays open until the rows are read")
+
+	for rows.Next() {
+	}
+	require.NoError(t, rows.Close())
+	server.End()
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "ListBanks", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestFailedStatementMarksItsSpan(t *testing.T) {
+	conn, err := OpenDB("tracing-fake", "")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, server := provider.Tracer("test").Start(context.Background(), "POST /v1/swift-codes")
+
+	_, err = conn.ExecContext(ctx, "-- name: CreateBank :one\nINSERT INTO banks DEFAULT VALUES")
+	assert.Error(t, err)
+	server.End()
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "CreateBank", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "connection reset", spans[0].Status().Description)
+}
+
+func TestStatementsOutsideATraceRecordNothing(t *testing.T) {
+	conn, err := OpenDB("tracing-fake", "")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	rows, err := conn.QueryContext(context.Background(), "-- name: ListBanks :many\nSELECT id FROM banks")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestQueryName(t *testing.T) {
+	assert.Equal(t, "GetBank", queryName("-- name: GetBank :one\nSELECT * FROM banks"))
+	assert.Equal(t, "query", queryName("SELECT 1"))
+}